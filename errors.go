@@ -0,0 +1,100 @@
+package helpers
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// MultiError collects several errors.
+// It is safe for concurrent use, and errors.Is / errors.As are checked against
+// every collected error.
+type MultiError struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// Append non-nil errors to the collection.
+// If one of them is a MultiError, its errors are added instead of the container.
+func (m *MultiError) Append(errs ...error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, err := range errs {
+		if err == nil {
+			continue
+		}
+		if other, ok := err.(*MultiError); ok {
+			if other == m {
+				continue
+			}
+			m.errs = append(m.errs, other.Errors()...)
+		} else {
+			m.errs = append(m.errs, err)
+		}
+	}
+}
+
+// Errors returns a copy of the collected errors.
+func (m *MultiError) Errors() []error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]error, len(m.errs))
+	copy(out, m.errs)
+	return out
+}
+
+// Len returns the number of collected errors.
+func (m *MultiError) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.errs)
+}
+
+// ErrorOrNil returns nil if no error was collected, the error itself if only
+// one was, and the MultiError otherwise.
+func (m *MultiError) ErrorOrNil() error {
+	errs := m.Errors()
+	switch len(errs) {
+	case 0:
+		return nil
+	case 1:
+		return errs[0]
+	}
+	return m
+}
+
+// Error lists all collected errors.
+func (m *MultiError) Error() string {
+	errs := m.Errors()
+	if len(errs) == 1 {
+		return errs[0].Error()
+	}
+	lines := make([]string, len(errs))
+	for i, err := range errs {
+		lines[i] = "\t* " + strings.Replace(err.Error(), "\n", "\n\t  ", -1)
+	}
+	return fmt.Sprintf("%d errors occurred:\n%s", len(errs), strings.Join(lines, "\n"))
+}
+
+// Unwrap returns the collected errors, so that errors.Is and errors.As consider
+// all of them.
+func (m *MultiError) Unwrap() []error {
+	return m.Errors()
+}
+
+// CombineErrors returns nil if all errors are nil, the only non-nil error if
+// there is just one, or a MultiError containing all non-nil errors.
+func CombineErrors(errs ...error) error {
+	m := &MultiError{}
+	m.Append(errs...)
+	return m.ErrorOrNil()
+}
+
+// CloseAndCombine closes c and combines its error with the one pointed to by
+// err. It is meant to be deferred in functions with a named error return value:
+//
+//	defer CloseAndCombine(&err, f)
+func CloseAndCombine(err *error, c io.Closer) {
+	*err = CombineErrors(*err, c.Close())
+}
@@ -0,0 +1,78 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type closer struct {
+	err error
+}
+
+func (c closer) Close() error {
+	return c.err
+}
+
+func TestHelpersMultiError(t *testing.T) {
+	fmt.Println("+ Testing Helpers/MultiError...")
+	assert := assert.New(t)
+
+	m := &MultiError{}
+	assert.Nil(m.ErrorOrNil())
+	m.Append(nil, os.ErrNotExist)
+	assert.Equal(os.ErrNotExist, m.ErrorOrNil(), "single error should be returned as is")
+
+	pathErr := &os.PathError{Op: "open", Path: "/nope", Err: os.ErrPermission}
+	m.Append(errors.New("first"), pathErr)
+	assert.Equal(3, m.Len())
+	err := m.ErrorOrNil()
+	assert.True(errors.Is(err, os.ErrNotExist))
+	assert.True(errors.Is(err, os.ErrPermission))
+	assert.False(errors.Is(err, os.ErrExist))
+	var target *os.PathError
+	assert.True(errors.As(err, &target))
+	assert.Equal("/nope", target.Path)
+	assert.Equal("3 errors occurred:\n\t* file does not exist\n\t* first\n\t* open /nope: permission denied", err.Error())
+
+	// nested MultiErrors are flattened
+	other := &MultiError{}
+	other.Append(m, errors.New("last"))
+	assert.Equal(4, other.Len())
+
+	// concurrent appends
+	c := &MultiError{}
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			c.Append(fmt.Errorf("error %d", i))
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(50, c.Len())
+}
+
+func TestHelpersCombineErrors(t *testing.T) {
+	fmt.Println("+ Testing Helpers/CombineErrors()...")
+	assert := assert.New(t)
+
+	assert.Nil(CombineErrors(nil, nil))
+	assert.Equal(os.ErrClosed, CombineErrors(nil, os.ErrClosed))
+
+	closeErr := func(err error, c closer) (res error) {
+		res = err
+		defer CloseAndCombine(&res, c)
+		return
+	}
+	assert.Nil(closeErr(nil, closer{}))
+	assert.Equal(os.ErrClosed, closeErr(nil, closer{os.ErrClosed}))
+	err := closeErr(os.ErrNotExist, closer{os.ErrClosed})
+	assert.True(errors.Is(err, os.ErrNotExist))
+	assert.True(errors.Is(err, os.ErrClosed))
+}
@@ -15,6 +15,51 @@ import (
 	i "github.com/barsanuphe/helpers/ui"
 )
 
+var (
+	// ErrSourceNotDirectory is returned when copying a directory that is not one.
+	ErrSourceNotDirectory = errors.New("source is not a directory")
+	// ErrDestinationExists is returned when the copy destination must not exist.
+	ErrDestinationExists = errors.New("destination already exists")
+	// ErrNonRegularFile is returned when a file to copy or overwrite is a
+	// directory, symlink, device, etc.
+	ErrNonRegularFile = errors.New("non-regular file")
+	// ErrNoUniqueFilename is returned when no free filename could be found.
+	ErrNoUniqueFilename = errors.New("could not find a unique filename")
+)
+
+// FileError records a failed filesystem operation and the paths involved.
+type FileError struct {
+	Op  string
+	Src string
+	Dst string
+	Err error
+}
+
+func (e *FileError) Error() string {
+	paths := e.Src
+	if e.Dst != "" {
+		paths += " -> " + e.Dst
+	}
+	return e.Op + " " + paths + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// wrapFileError adds context to err, unless it already has some.
+func wrapFileError(op, src, dst string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var fe *FileError
+	if errors.As(err, &fe) {
+		return err
+	}
+	return &FileError{Op: op, Src: src, Dst: dst, Err: err}
+}
+
 // DirectoryExists checks if a directory exists.
 func DirectoryExists(path string) (res bool) {
 	info, err := os.Stat(path)
@@ -126,23 +171,27 @@ func DeleteEmptyFolders(root string, ui i.UserInterface) (err error) {
 // CopyDir recursively copies a directory tree, attempting to preserve permissions.
 // Source directory must exist, destination directory must *not* exist.
 // Symlinks are ignored and skipped.
+// Errors are returned as *FileError.
 func CopyDir(src string, dst string) (err error) {
 	src = filepath.Clean(src)
 	dst = filepath.Clean(dst)
+	defer func() {
+		err = wrapFileError("copy dir", src, dst, err)
+	}()
 
 	si, err := os.Stat(src)
 	if err != nil {
 		return err
 	}
 	if !si.IsDir() {
-		return errors.New("source is not a directory")
+		return ErrSourceNotDirectory
 	}
 	_, err = os.Stat(dst)
 	if err != nil && !os.IsNotExist(err) {
 		return
 	}
 	if err == nil {
-		return errors.New("destination already exists")
+		return ErrDestinationExists
 	}
 	err = os.MkdirAll(dst, si.Mode())
 	if err != nil {
@@ -176,7 +225,12 @@ func CopyDir(src string, dst string) (err error) {
 
 // CopyFile copies a file from src to dst. If src and dst files exist, and are
 // the same, then return success. Copy the file contents from src to dst.
+// Errors are returned as *FileError.
 func CopyFile(src, dst string) (err error) {
+	defer func() {
+		err = wrapFileError("copy file", src, dst, err)
+	}()
+
 	sfi, err := os.Stat(src)
 	if err != nil {
 		return
@@ -184,7 +238,7 @@ func CopyFile(src, dst string) (err error) {
 	if !sfi.Mode().IsRegular() {
 		// cannot copy non-regular files (e.g., directories,
 		// symlinks, devices, etc.)
-		return fmt.Errorf("CopyFile: non-regular source file %s (%q)", sfi.Name(), sfi.Mode().String())
+		return fmt.Errorf("%w: source is %q", ErrNonRegularFile, sfi.Mode().String())
 	}
 	dfi, err := os.Stat(dst)
 	if err != nil {
@@ -193,7 +247,7 @@ func CopyFile(src, dst string) (err error) {
 		}
 	} else {
 		if !(dfi.Mode().IsRegular()) {
-			return fmt.Errorf("CopyFile: non-regular destination file %s (%q)", dfi.Name(), dfi.Mode().String())
+			return fmt.Errorf("%w: destination is %q", ErrNonRegularFile, dfi.Mode().String())
 		}
 		if os.SameFile(sfi, dfi) {
 			return
@@ -212,17 +266,12 @@ func copyFileContents(src, dst string) (err error) {
 	if err != nil {
 		return
 	}
-	defer in.Close()
+	defer CloseAndCombine(&err, in)
 	out, err := os.Create(dst)
 	if err != nil {
 		return
 	}
-	defer func() {
-		cerr := out.Close()
-		if err == nil {
-			err = cerr
-		}
-	}()
+	defer CloseAndCombine(&err, out)
 	if _, err = io.Copy(out, in); err != nil {
 		return
 	}
@@ -247,7 +296,11 @@ func CalculateSHA256(filename string) (string, error) {
 }
 
 // GetUniqueTimestampedFilename for a given filename.
+// Errors are returned as *FileError.
 func GetUniqueTimestampedFilename(dir, filename string) (uniqueFilename string, err error) {
+	defer func() {
+		err = wrapFileError("unique filename", filepath.Join(dir, filename), "", err)
+	}()
 	// create dir if necessary
 	if !DirectoryExists(dir) {
 		err = os.MkdirAll(dir, 0700)
@@ -261,7 +314,10 @@ func GetUniqueTimestampedFilename(dir, filename string) (uniqueFilename string,
 	ext := filepath.Ext(filename)
 	filenameBase := strings.TrimSuffix(filepath.Base(filename), ext)
 	attempts := 0
-	for !uniqueNameFound || attempts > 50 {
+	for !uniqueNameFound {
+		if attempts > 50 {
+			return "", ErrNoUniqueFilename
+		}
 		suffix := ""
 		if attempts > 0 {
 			suffix = fmt.Sprintf("_%d", attempts)
@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -97,3 +98,37 @@ func TestHelpersDeleteFolders(t *testing.T) {
 	// test
 	// remove all
 }
+
+func TestHelpersCopyErrors(t *testing.T) {
+	fmt.Println("+ Testing Helpers/Copy() errors...")
+	assert := assert.New(t)
+	testDir, err := os.Getwd()
+	require.Nil(t, err, "Error getting current directory")
+	testDir = filepath.Join(testDir, "test")
+	epub := filepath.Join(testDir, epubs[0].filename)
+
+	// source is a file
+	err = CopyDir(epub, filepath.Join(testDir, "copy"))
+	assert.True(errors.Is(err, ErrSourceNotDirectory))
+	var fe *FileError
+	require.True(t, errors.As(err, &fe))
+	assert.Equal("copy dir", fe.Op)
+	assert.Equal(epub, fe.Src)
+
+	// destination exists
+	err = CopyDir(testDir, testDir)
+	assert.True(errors.Is(err, ErrDestinationExists))
+
+	// non-regular files
+	err = CopyFile(testDir, filepath.Join(testDir, "copy"))
+	assert.True(errors.Is(err, ErrNonRegularFile))
+	err = CopyFile(epub, testDir)
+	assert.True(errors.Is(err, ErrNonRegularFile))
+
+	// missing source
+	err = CopyFile(filepath.Join(testDir, "doesnotexist"), filepath.Join(testDir, "copy"))
+	assert.True(errors.Is(err, os.ErrNotExist))
+	require.True(t, errors.As(err, &fe))
+	assert.Equal("copy file", fe.Op)
+	assert.Equal(filepath.Join(testDir, "copy"), fe.Dst)
+}
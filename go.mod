@@ -0,0 +1,26 @@
+module github.com/barsanuphe/helpers
+
+go 1.20
+
+require (
+	github.com/BurntSushi/toml v1.3.2
+	github.com/mattn/go-runewidth v0.0.15
+	github.com/op/go-logging v0.0.0-20160315200505-970db520ece7
+	github.com/stretchr/testify v1.8.4
+	github.com/tj/go-spin v1.1.0
+	github.com/ttacon/chalk v0.0.0-20160626202418-22c06c80ed31
+	golang.org/x/sys v0.15.0
+	golang.org/x/term v0.15.0
+	gopkg.in/yaml.v2 v2.4.0
+	launchpad.net/go-xdg v0.0.0-00010101000000-000000000000
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/rivo/uniseg v0.2.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// launchpad.net is only served over bzr, use the local copy.
+replace launchpad.net/go-xdg => ./third_party/go-xdg
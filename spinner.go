@@ -0,0 +1,137 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tj/go-spin"
+
+	i "github.com/barsanuphe/helpers/ui"
+)
+
+// Spinner frame sets.
+var (
+	SpinnerDots   = spin.Box1
+	SpinnerLine   = spin.Spin1
+	SpinnerCircle = spin.Spin4
+	SpinnerArrows = spin.Spin9
+)
+
+// Spinner displays a title and an animation while something is happening.
+// Frames are only drawn if Output is a terminal, otherwise only the final
+// Done/KO line is written.
+type Spinner struct {
+	// Title displayed before the animation.
+	Title string
+	// Frames of the animation, one rune per frame.
+	Frames string
+	// Interval between two frames.
+	Interval time.Duration
+	// Output is where the spinner is drawn, os.Stdout by default.
+	Output io.Writer
+
+	mu         sync.Mutex
+	stop       chan struct{}
+	done       chan struct{}
+	unregister func()
+}
+
+// NewSpinner returns a Spinner with default frames, writing to os.Stdout.
+func NewSpinner(title string) *Spinner {
+	return &Spinner{
+		Title:    title,
+		Frames:   SpinnerDots,
+		Interval: 100 * time.Millisecond,
+		Output:   os.Stdout,
+	}
+}
+
+// Start drawing the spinner until Stop is called or ctx is cancelled.
+// Starting a spinner that is already running does nothing.
+// If the program is interrupted by a signal handled by ui.HandleSignals, the
+// spinner is stopped as if it had failed.
+func (s *Spinner) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop != nil {
+		return
+	}
+	if s.Output == nil {
+		s.Output = os.Stdout
+	}
+	if s.Interval <= 0 {
+		s.Interval = 100 * time.Millisecond
+	}
+	frames := spin.New()
+	if s.Frames != "" {
+		frames.Set(s.Frames)
+	}
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+	s.unregister = i.RegisterCleanup(func() {
+		s.Stop(i.ErrInterrupted)
+	})
+
+	go func(stop, done chan struct{}, out io.Writer, interactive bool) {
+		defer close(done)
+		ticker := time.NewTicker(s.Interval)
+		defer ticker.Stop()
+		for {
+			if interactive {
+				fmt.Fprintf(out, "\r%s... %s ", s.Title, frames.Next())
+			}
+			select {
+			case <-ticker.C:
+			case <-stop:
+				return
+			case <-ctx.Done():
+				return
+			}
+		}
+	}(s.stop, s.done, s.Output, i.IsTerminal(s.Output))
+}
+
+// Stop the spinner, and display Done or KO depending on err.
+// Stopping a spinner that is not running does nothing.
+func (s *Spinner) Stop(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stop == nil {
+		return
+	}
+	close(s.stop)
+	<-s.done
+	s.unregister()
+	s.stop = nil
+	s.done = nil
+	if err != nil {
+		fmt.Fprintf(s.Output, "\r%s... KO.\n", s.Title)
+	} else {
+		fmt.Fprintf(s.Output, "\r%s... Done.\n", s.Title)
+	}
+}
+
+// Run f while displaying the spinner.
+// If ctx is cancelled before f returns, Run returns ctx.Err() immediately;
+// f is expected to stop when ctx is done.
+func (s *Spinner) Run(ctx context.Context, f func(context.Context) error) error {
+	s.Start(ctx)
+	// buffered so that f can return even if nobody waits for it anymore
+	result := make(chan error, 1)
+	go func() {
+		result <- f(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	s.Stop(err)
+	return err
+}
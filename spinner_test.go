@@ -0,0 +1,102 @@
+package helpers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	i "github.com/barsanuphe/helpers/ui"
+)
+
+// waitForGoroutines returns the number of goroutines once it is at most
+// expected, or after a timeout.
+func waitForGoroutines(expected int) int {
+	deadline := time.Now().Add(time.Second)
+	for {
+		n := runtime.NumGoroutine()
+		if n <= expected || time.Now().After(deadline) {
+			return n
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestHelpersSpinner(t *testing.T) {
+	fmt.Println("+ Testing Helpers/Spinner...")
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+
+	output := &bytes.Buffer{}
+	for i := 0; i < 10; i++ {
+		s := NewSpinner("Working")
+		s.Output = output
+		s.Interval = time.Millisecond
+		s.Frames = SpinnerLine
+		err := s.Run(context.Background(), func(context.Context) error {
+			time.Sleep(5 * time.Millisecond)
+			return nil
+		})
+		assert.Nil(err)
+	}
+	failure := errors.New("failure")
+	s := NewSpinner("Failing")
+	s.Output = output
+	assert.Equal(failure, s.Run(context.Background(), func(context.Context) error { return failure }))
+	// not a terminal, only the results are written
+	assert.True(strings.HasPrefix(output.String(), "\rWorking... Done.\n"))
+	assert.Contains(output.String(), "\rFailing... KO.\n")
+
+	// stopping twice, or a spinner never started
+	s.Stop(nil)
+	NewSpinner("Idle").Stop(nil)
+
+	assert.Equal(before, waitForGoroutines(before), "goroutines leaked")
+}
+
+func TestHelpersSpinnerCancel(t *testing.T) {
+	fmt.Println("+ Testing Helpers/Spinner cancellation...")
+	assert := assert.New(t)
+	before := runtime.NumGoroutine()
+
+	output := &bytes.Buffer{}
+	s := NewSpinner("Cancelled")
+	s.Output = output
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	err := s.Run(ctx, func(ctx context.Context) error {
+		<-ctx.Done()
+		return nil
+	})
+	assert.Equal(context.Canceled, err)
+	assert.Equal("\rCancelled... KO.\n", output.String())
+
+	// ctx cancelled while the spinner is drawn, without Run
+	ctx, cancel = context.WithCancel(context.Background())
+	s.Start(ctx)
+	cancel()
+	s.Stop(nil)
+
+	assert.Equal(before, waitForGoroutines(before), "goroutines leaked")
+}
+
+func TestHelpersSpinnerInterrupted(t *testing.T) {
+	fmt.Println("+ Testing Helpers/Spinner interrupted...")
+	assert := assert.New(t)
+
+	output := &bytes.Buffer{}
+	s := NewSpinner("Interrupted")
+	s.Output = output
+	s.Start(context.Background())
+	// what ui.HandleSignals does after a signal
+	i.RunCleanups()
+	assert.Equal("\rInterrupted... KO.\n", output.String())
+	s.Stop(nil)
+	assert.Equal("\rInterrupted... KO.\n", output.String())
+}
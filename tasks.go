@@ -0,0 +1,212 @@
+package helpers
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/tj/go-spin"
+
+	i "github.com/barsanuphe/helpers/ui"
+)
+
+type taskState int
+
+const (
+	taskWaiting taskState = iota
+	taskRunning
+	taskDone
+	taskFailed
+)
+
+type task struct {
+	title   string
+	f       func() error
+	state   taskState
+	start   time.Time
+	elapsed time.Duration
+	err     error
+	spinner *spin.Spinner
+}
+
+// status line of a task, without line return.
+func (t *task) status(now time.Time) string {
+	switch t.state {
+	case taskRunning:
+		return fmt.Sprintf("%s... %s (%s)", t.title, t.spinner.Next(), now.Sub(t.start).Round(time.Second/10))
+	case taskDone:
+		return fmt.Sprintf("%s... Done. (%s)", t.title, t.elapsed.Round(time.Second/10))
+	case taskFailed:
+		return fmt.Sprintf("%s... KO: %s", t.title, t.err.Error())
+	}
+	return fmt.Sprintf("%s... waiting", t.title)
+}
+
+// TaskGroup runs functions concurrently, displaying the status of each one.
+// On a terminal, each task gets a live status line with a spinner; otherwise
+// a line is logged each time a task starts or ends.
+type TaskGroup struct {
+	// Workers is the maximum number of tasks running at the same time.
+	Workers int
+	// Output is where statuses are displayed, os.Stdout by default.
+	Output io.Writer
+	// Interval between two refreshes of the status lines.
+	Interval    time.Duration
+	interactive bool
+	tasks       []*task
+	mu          sync.Mutex
+}
+
+// NewTaskGroup returns a TaskGroup running at most workers tasks at once.
+func NewTaskGroup(workers int) *TaskGroup {
+	return &TaskGroup{
+		Workers:  workers,
+		Output:   os.Stdout,
+		Interval: 100 * time.Millisecond,
+	}
+}
+
+// Add a task to the group.
+func (g *TaskGroup) Add(title string, f func() error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tasks = append(g.tasks, &task{title: title, f: f, spinner: spin.New()})
+}
+
+// Run all tasks and wait for them to finish.
+// The errors of failed tasks are returned, prefixed with their titles.
+// If the program is interrupted by a signal handled by ui.HandleSignals,
+// running and waiting tasks are displayed as interrupted.
+func (g *TaskGroup) Run() error {
+	g.mu.Lock()
+	tasks := g.tasks
+	g.mu.Unlock()
+
+	workers := g.Workers
+	if workers < 1 || workers > len(tasks) {
+		workers = len(tasks)
+	}
+	if g.Output == nil {
+		g.Output = os.Stdout
+	}
+	if g.Interval <= 0 {
+		g.Interval = 100 * time.Millisecond
+	}
+	g.interactive = i.IsTerminal(g.Output)
+
+	queue := make(chan *task)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range queue {
+				g.run(t)
+			}
+		}()
+	}
+
+	// rendering of the status lines
+	stopRendering := make(chan struct{})
+	renderingDone := make(chan struct{})
+	if g.interactive {
+		go func() {
+			defer close(renderingDone)
+			g.render(tasks, false)
+			ticker := time.NewTicker(g.Interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					g.render(tasks, true)
+				case <-stopRendering:
+					g.render(tasks, true)
+					return
+				}
+			}
+		}()
+	} else {
+		close(renderingDone)
+	}
+
+	unregister := i.RegisterCleanup(func() {
+		g.interrupt(tasks)
+	})
+	for _, t := range tasks {
+		queue <- t
+	}
+	close(queue)
+	wg.Wait()
+	close(stopRendering)
+	<-renderingDone
+	unregister()
+
+	errs := &MultiError{}
+	for _, t := range tasks {
+		if t.err != nil {
+			errs.Append(fmt.Errorf("%s: %w", t.title, t.err))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// run a task, updating its state.
+func (g *TaskGroup) run(t *task) {
+	g.mu.Lock()
+	t.state = taskRunning
+	t.start = time.Now()
+	if !g.interactive {
+		fmt.Fprintf(g.Output, "%s... started\n", t.title)
+	}
+	g.mu.Unlock()
+
+	err := t.f()
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	t.elapsed = time.Since(t.start)
+	t.err = err
+	if err != nil {
+		t.state = taskFailed
+	} else {
+		t.state = taskDone
+	}
+	if !g.interactive {
+		fmt.Fprintln(g.Output, t.status(time.Now()))
+	}
+}
+
+// interrupt unfinished tasks, and display their final status.
+func (g *TaskGroup) interrupt(tasks []*task) {
+	g.mu.Lock()
+	for _, t := range tasks {
+		if t.state != taskRunning && t.state != taskWaiting {
+			continue
+		}
+		t.state = taskFailed
+		t.err = i.ErrInterrupted
+		if !g.interactive {
+			fmt.Fprintln(g.Output, t.status(time.Now()))
+		}
+	}
+	g.mu.Unlock()
+	if g.interactive {
+		g.render(tasks, true)
+	}
+}
+
+// render all status lines, overwriting the previous ones if necessary.
+func (g *TaskGroup) render(tasks []*task, overwrite bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if overwrite && len(tasks) != 0 {
+		// move cursor back up to the first line
+		fmt.Fprintf(g.Output, "\033[%dA", len(tasks))
+	}
+	now := time.Now()
+	for _, t := range tasks {
+		fmt.Fprintf(g.Output, "\r\033[K%s\n", t.status(now))
+	}
+}
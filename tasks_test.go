@@ -0,0 +1,86 @@
+package helpers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	i "github.com/barsanuphe/helpers/ui"
+)
+
+func TestHelpersTaskGroup(t *testing.T) {
+	fmt.Println("+ Testing Helpers/TaskGroup...")
+	assert := assert.New(t)
+
+	var running, maxRunning int32
+	work := func() error {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			max := atomic.LoadInt32(&maxRunning)
+			if current <= max || atomic.CompareAndSwapInt32(&maxRunning, max, current) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+	failure := errors.New("failure")
+
+	output := &bytes.Buffer{}
+	g := NewTaskGroup(2)
+	g.Output = output
+	for i := 0; i < 5; i++ {
+		g.Add(fmt.Sprintf("task %d", i), work)
+	}
+	g.Add("failing task", func() error { return failure })
+
+	err := g.Run()
+	assert.NotNil(err)
+	assert.True(errors.Is(err, failure))
+	assert.Equal("failing task: failure", err.Error())
+	assert.Equal(int32(2), maxRunning, "at most 2 tasks should run at the same time")
+
+	// not a terminal: one line when starting, one when done
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	assert.Equal(12, len(lines))
+	assert.Contains(output.String(), "task 3... Done.")
+	assert.Contains(output.String(), "failing task... KO: failure")
+
+	// no tasks
+	assert.Nil(NewTaskGroup(2).Run())
+}
+
+func TestHelpersTaskGroupInterrupted(t *testing.T) {
+	fmt.Println("+ Testing Helpers/TaskGroup interrupted...")
+	assert := assert.New(t)
+
+	output := &bytes.Buffer{}
+	g := NewTaskGroup(1)
+	g.Output = output
+	started := make(chan struct{})
+	release := make(chan struct{})
+	g.Add("blocked task", func() error {
+		close(started)
+		<-release
+		return nil
+	})
+	g.Add("waiting task", func() error { return nil })
+	result := make(chan error)
+	go func() {
+		result <- g.Run()
+	}()
+	<-started
+	// what ui.HandleSignals does after a signal
+	i.RunCleanups()
+	assert.Contains(output.String(), "blocked task... KO: interrupted")
+	assert.Contains(output.String(), "waiting task... KO: interrupted")
+	close(release)
+	<-result
+}
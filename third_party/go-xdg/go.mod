@@ -0,0 +1,3 @@
+module launchpad.net/go-xdg
+
+go 1.20
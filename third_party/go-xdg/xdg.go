@@ -0,0 +1,78 @@
+// Package xdg finds and creates files in the XDG base directories, as defined
+// by the XDG Base Directory Specification.
+package xdg
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// XDGDir is a kind of XDG base directory, with a home directory for the user
+// and system directories to search.
+type XDGDir struct {
+	homeEnv     string
+	homeDefault string
+	dirsEnv     string
+	dirsDefault string
+}
+
+// XDG base directories.
+var (
+	Data   = &XDGDir{"XDG_DATA_HOME", ".local/share", "XDG_DATA_DIRS", "/usr/local/share:/usr/share"}
+	Config = &XDGDir{"XDG_CONFIG_HOME", ".config", "XDG_CONFIG_DIRS", "/etc/xdg"}
+	Cache  = &XDGDir{"XDG_CACHE_HOME", ".cache", "", ""}
+)
+
+// Home directory of the user for this kind of files.
+func (x *XDGDir) Home() string {
+	if home := os.Getenv(x.homeEnv); filepath.IsAbs(home) {
+		return home
+	}
+	return filepath.Join(os.Getenv("HOME"), x.homeDefault)
+}
+
+// Dirs are the directories to search, in order of preference, starting with
+// Home.
+func (x *XDGDir) Dirs() []string {
+	dirs := []string{x.Home()}
+	if x.dirsEnv == "" {
+		return dirs
+	}
+	list := os.Getenv(x.dirsEnv)
+	if list == "" {
+		list = x.dirsDefault
+	}
+	for _, dir := range strings.Split(list, ":") {
+		if filepath.IsAbs(dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// Find the path suffix in the directories, returning the first match.
+func (x *XDGDir) Find(suffix string) (string, error) {
+	for _, dir := range x.Dirs() {
+		path := filepath.Join(dir, suffix)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", errors.New("could not find " + suffix + " in XDG directories")
+}
+
+// Ensure the path suffix exists as a file in Home, creating it and its
+// directories if needed, and return its full path.
+func (x *XDGDir) Ensure(suffix string) (string, error) {
+	path := filepath.Join(x.Home(), suffix)
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return "", err
+	}
+	f, err := os.OpenFile(path, os.O_RDONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return "", err
+	}
+	return path, f.Close()
+}
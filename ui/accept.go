@@ -0,0 +1,244 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Answers recognized by Accept and its variants, regardless of case, unless
+// translated by the Catalog of the UI language.
+// The first answer of each list is the one shown to the user.
+var (
+	YesAnswers    = []string{"y", "yes"}
+	NoAnswers     = []string{"n", "no"}
+	AlwaysAnswers = []string{"a", "always"}
+	NeverAnswers  = []string{"v", "never"}
+)
+
+const rememberHelp = "(%s: always, %s: never for this session)"
+
+// DefaultAnswer to a yes/no question.
+type DefaultAnswer int
+
+const (
+	// NoDefault requires an explicit answer.
+	NoDefault DefaultAnswer = iota
+	// DefaultYes is used for empty answers.
+	DefaultYes
+	// DefaultNo is used for empty answers.
+	DefaultNo
+)
+
+// AcceptOptions configures AcceptWithOptions.
+type AcceptOptions struct {
+	// Default answer, returned for empty input.
+	Default DefaultAnswer
+	// Timeout after which the default answer is returned, if there is one.
+	// 0 waits forever.
+	Timeout time.Duration
+	// Remember lets the user answer always or never, in which case the
+	// question is not asked again during the session.
+	Remember bool
+	// Key identifies the question for Remember, the question itself if empty.
+	Key string
+}
+
+// session state, shared by all copies of a UI.
+type session struct {
+	mu sync.Mutex
+	// answers remembered for the session
+	answers map[string]bool
+	// pending is a wait for input in the background after a timeout. It
+	// only fills the buffer of the reader, leaving the input to the next
+	// call reading it.
+	pending chan error
+}
+
+// sessionMu guards the creation of sessions.
+var sessionMu sync.Mutex
+
+// session of the UI, created if necessary.
+func (ui *UI) session() *session {
+	sessionMu.Lock()
+	defer sessionMu.Unlock()
+	if ui.state == nil {
+		ui.state = &session{answers: make(map[string]bool)}
+	}
+	return ui.state
+}
+
+// dropLate discards a line typed before the current prompt was shown, if
+// the wait for input in the background is over: it answered a question that
+// timed out. It must be called with s.mu held.
+func (s *session) dropLate(r *bufio.Reader) {
+	if s.pending == nil {
+		return
+	}
+	select {
+	case <-s.pending:
+		s.pending = nil
+		r.ReadString('\n')
+	default:
+	}
+}
+
+// waitInput waits for input if a question timed out and input is still
+// awaited in the background, so that the reader is not used concurrently.
+// Every call reading user input must call it first. A line typed before the
+// prompt was shown is discarded. It returns true if it waited.
+func (ui UI) waitInput() bool {
+	if ui.state == nil {
+		return false
+	}
+	ui.state.mu.Lock()
+	ui.state.dropLate(ui.reader())
+	c := ui.state.pending
+	ui.state.mu.Unlock()
+	if c == nil {
+		return false
+	}
+	<-c
+	ui.state.mu.Lock()
+	ui.state.pending = nil
+	ui.state.mu.Unlock()
+	return true
+}
+
+// readInputTimeout reads a line, giving up after timeout.
+// Input is still awaited in the background, without being read: it is read
+// by the next call reading input, unless it is typed before the next prompt.
+func (ui *UI) readInputTimeout(timeout time.Duration) (line string, ok bool, err error) {
+	s := ui.session()
+	r := ui.reader()
+	s.mu.Lock()
+	s.dropLate(r)
+	if s.pending == nil {
+		c := make(chan error, 1)
+		s.pending = c
+		go func() {
+			_, err := r.Peek(1)
+			c <- err
+		}()
+	}
+	c := s.pending
+	s.mu.Unlock()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-c:
+		s.mu.Lock()
+		s.pending = nil
+		s.mu.Unlock()
+		line, err := ui.readInputLine()
+		return line, true, err
+	case <-timer.C:
+		return "", false, nil
+	}
+}
+
+// isAnswer checks if input is one of the answers, regardless of case.
+func isAnswer(input string, answers []string) bool {
+	for _, a := range answers {
+		if strings.EqualFold(input, a) {
+			return true
+		}
+	}
+	return false
+}
+
+// AcceptDefault asks a yes/no question, returning the default answer if the
+// user just hits Enter.
+func (ui *UI) AcceptDefault(question string, defaultYes bool) bool {
+	options := AcceptOptions{Default: DefaultNo}
+	if defaultYes {
+		options.Default = DefaultYes
+	}
+	answer, err := ui.AcceptWithOptions(question, options)
+	if err != nil {
+		return defaultYes
+	}
+	return answer
+}
+
+// AcceptWithOptions asks a yes/no question until the answer is valid.
+func (ui *UI) AcceptWithOptions(question string, options AcceptOptions) (bool, error) {
+	key := options.Key
+	if key == "" {
+		key = question
+	}
+	s := ui.session()
+	if options.Remember {
+		s.mu.Lock()
+		answer, ok := s.answers[key]
+		s.mu.Unlock()
+		if ok {
+			return answer, nil
+		}
+	}
+	if a, auto := ui.automation(); auto {
+		answer := a.AssumeYes
+		if options.Default != NoDefault {
+			answer = options.Default == DefaultYes
+		}
+		ui.logDecision("%s: answered %t", question, answer)
+		return answer, nil
+	}
+
+	yesAnswers, noAnswers, alwaysAnswers, neverAnswers := ui.answers()
+	yes, no := yesAnswers[0], noAnswers[0]
+	switch options.Default {
+	case DefaultYes:
+		yes = strings.ToUpper(yes)
+	case DefaultNo:
+		no = strings.ToUpper(no)
+	default:
+		yes, no = strings.ToUpper(yes), strings.ToUpper(no)
+	}
+	choices := yes + "/" + no
+	if options.Remember {
+		choices += "/" + alwaysAnswers[0] + "/" + neverAnswers[0]
+		question += " " + ui.T(rememberHelp, alwaysAnswers[0], neverAnswers[0])
+	}
+
+	for errs := 0; errs <= maxErrors; errs++ {
+		ui.Choice("%s [%s]: ", question, choices)
+		var input string
+		var err error
+		if options.Timeout > 0 && options.Default != NoDefault {
+			var answered bool
+			input, answered, err = ui.readInputTimeout(options.Timeout)
+			if !answered {
+				fmt.Println()
+				return options.Default == DefaultYes, nil
+			}
+		} else {
+			input, err = ui.GetInput()
+		}
+		if err != nil {
+			return options.Default == DefaultYes, err
+		}
+
+		switch {
+		case input == "" && options.Default != NoDefault:
+			return options.Default == DefaultYes, nil
+		case isAnswer(input, yesAnswers):
+			return true, nil
+		case isAnswer(input, noAnswers):
+			return false, nil
+		case options.Remember && (isAnswer(input, alwaysAnswers) || isAnswer(input, neverAnswers)):
+			answer := isAnswer(input, alwaysAnswers)
+			s.mu.Lock()
+			s.answers[key] = answer
+			s.mu.Unlock()
+			return answer, nil
+		}
+		ui.Warning(ui.T(invalidChoice))
+	}
+	ui.Warning(ui.T(tooManyErrors))
+	return options.Default == DefaultYes, errors.New(invalidChoice)
+}
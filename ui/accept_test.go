@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIAcceptWithOptions(t *testing.T) {
+	fmt.Println("+ Testing UI/AcceptWithOptions()...")
+	assert := assert.New(t)
+	ui := &UI{}
+
+	// defaults
+	ui.SetInput(strings.NewReader("\n\nYES\nmaybe\nno\n"))
+	assert.True(ui.AcceptDefault("Continue?", true))
+	assert.False(ui.AcceptDefault("Continue?", false))
+	assert.True(ui.AcceptDefault("Continue?", false))
+	assert.False(ui.AcceptDefault("Continue?", true), "invalid answers should be asked again")
+
+	// no default: empty answers are invalid
+	ui.SetInput(strings.NewReader("\ny\n"))
+	answer, err := ui.AcceptWithOptions("Continue?", AcceptOptions{})
+	assert.Nil(err)
+	assert.True(answer)
+	ui.SetInput(strings.NewReader(strings.Repeat("\n", maxErrors+1)))
+	_, err = ui.AcceptWithOptions("Continue?", AcceptOptions{})
+	assert.NotNil(err)
+
+	// localized answers
+	YesAnswers, NoAnswers = []string{"o", "oui"}, []string{"n", "non"}
+	ui.SetInput(strings.NewReader("oui\ny\nnon\n"))
+	assert.True(ui.AcceptDefault("Continuer ?", false))
+	assert.False(ui.AcceptDefault("Continuer ?", true))
+	YesAnswers, NoAnswers = []string{"y", "yes"}, []string{"n", "no"}
+
+	// remembering for the session
+	ui.SetInput(strings.NewReader("always\nnever\n"))
+	options := AcceptOptions{Remember: true, Default: DefaultNo}
+	for i := 0; i < 3; i++ {
+		answer, err = ui.AcceptWithOptions("Overwrite?", options)
+		assert.Nil(err)
+		assert.True(answer)
+	}
+	options.Key = "delete"
+	for i := 0; i < 3; i++ {
+		answer, err = ui.AcceptWithOptions("Delete file?", options)
+		assert.Nil(err)
+		assert.False(answer)
+	}
+	// "always" is invalid if the answer cannot be remembered
+	ui.SetInput(strings.NewReader("always\nyes\n"))
+	assert.True(ui.AcceptDefault("Overwrite?", false))
+}
+
+func TestUIAcceptTimeout(t *testing.T) {
+	fmt.Println("+ Testing UI/AcceptWithOptions() timeout...")
+	assert := assert.New(t)
+	ui := &UI{}
+
+	r, w := io.Pipe()
+	ui.SetInput(r)
+	start := time.Now()
+	answer, err := ui.AcceptWithOptions("Continue?", AcceptOptions{Default: DefaultYes, Timeout: 20 * time.Millisecond})
+	assert.Nil(err)
+	assert.True(answer)
+	assert.True(time.Since(start) < time.Second)
+
+	// a late answer typed before the next prompt is discarded
+	_, err = io.WriteString(w, "typed late\n")
+	assert.Nil(err)
+	for ui.state.mu.Lock(); len(ui.state.pending) == 0; ui.state.mu.Lock() {
+		ui.state.mu.Unlock()
+		time.Sleep(time.Millisecond)
+	}
+	ui.state.mu.Unlock()
+	go io.WriteString(w, "next answer\n")
+	input, err := ui.GetInput()
+	assert.Nil(err)
+	assert.Equal("next answer", input)
+
+	// the line typed after the next prompt is not lost
+	answer, err = ui.AcceptWithOptions("Continue?", AcceptOptions{Default: DefaultYes, Timeout: 20 * time.Millisecond})
+	assert.Nil(err)
+	assert.True(answer)
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		io.WriteString(w, "typed after\n")
+	}()
+	input, err = ui.GetInput()
+	assert.Nil(err)
+	assert.Equal("typed after", input)
+
+	// answer before the timeout
+	go io.WriteString(w, "n\n")
+	answer, err = ui.AcceptWithOptions("Continue?", AcceptOptions{Default: DefaultYes, Timeout: time.Second})
+	assert.Nil(err)
+	assert.False(answer)
+}
+
+func TestUISessionConcurrent(t *testing.T) {
+	fmt.Println("+ Testing UI/session() concurrently...")
+	ui := &UI{}
+	sessions := make(chan *session, 10)
+	for n := 0; n < cap(sessions); n++ {
+		go func() { sessions <- ui.session() }()
+	}
+	first := <-sessions
+	for n := 1; n < cap(sessions); n++ {
+		assert.True(t, first == <-sessions, "a single session is created")
+	}
+}
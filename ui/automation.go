@@ -0,0 +1,159 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Environment variables enabling and configuring the non-interactive mode.
+const (
+	// EnvNonInteractive enables the non-interactive mode if set to a true value.
+	EnvNonInteractive = "UI_NONINTERACTIVE"
+	// EnvAssumeYes sets the answer to yes/no questions without default.
+	EnvAssumeYes = "UI_ASSUME_YES"
+	// EnvSelectPolicy sets the SelectPolicy by name: first, local, online or none.
+	EnvSelectPolicy = "UI_SELECT_POLICY"
+)
+
+// ErrNonInteractive is returned when input is required in non-interactive mode.
+var ErrNonInteractive = errors.New("user input required in non-interactive mode")
+
+// SelectPolicy decides which option is selected in non-interactive mode.
+type SelectPolicy int
+
+const (
+	// SelectFirst selects the first option.
+	SelectFirst SelectPolicy = iota
+	// SelectLocal selects the first option tagged as local.
+	SelectLocal
+	// SelectOnline selects the first option tagged as online.
+	SelectOnline
+	// SelectNone returns an error.
+	SelectNone
+)
+
+var selectPolicies = map[string]SelectPolicy{
+	"first":  SelectFirst,
+	"local":  SelectLocal,
+	"online": SelectOnline,
+	"none":   SelectNone,
+}
+
+// ParseSelectPolicy returns the policy for a name: first, local, online or none.
+func ParseSelectPolicy(name string) (SelectPolicy, error) {
+	policy, ok := selectPolicies[strings.ToLower(name)]
+	if !ok {
+		return SelectNone, fmt.Errorf("unknown select policy %q", name)
+	}
+	return policy, nil
+}
+
+// Automation defines how user decisions are taken in non-interactive mode:
+// Accept returns AssumeYes (or the default answer if there is one),
+// UpdateValue and Edit keep the current value, SelectOption picks an option
+// according to Select, and methods requiring input return ErrNonInteractive.
+// SelectOptions returns only the option picked according to Select, never
+// several options.
+// Every automatic decision is logged.
+type Automation struct {
+	Enabled   bool
+	AssumeYes bool
+	Select    SelectPolicy
+}
+
+// AutomationFromEnv reads the non-interactive configuration from the
+// environment.
+// An invalid EnvNonInteractive value, such as "yes", still enables the
+// non-interactive mode, without selecting anything, rather than waiting for
+// input that will never come.
+func AutomationFromEnv() (Automation, error) {
+	a := Automation{}
+	if value := os.Getenv(EnvNonInteractive); value != "" {
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			a.Enabled, a.Select = true, SelectNone
+			return a, fmt.Errorf("invalid %s: %w", EnvNonInteractive, err)
+		}
+		a.Enabled = enabled
+	}
+	if value := os.Getenv(EnvAssumeYes); value != "" {
+		yes, err := strconv.ParseBool(value)
+		if err != nil {
+			return a, fmt.Errorf("invalid %s: %w", EnvAssumeYes, err)
+		}
+		a.AssumeYes = yes
+	}
+	if value := os.Getenv(EnvSelectPolicy); value != "" {
+		policy, err := ParseSelectPolicy(value)
+		if err != nil {
+			return a, err
+		}
+		a.Select = policy
+	}
+	return a, nil
+}
+
+// automation returns the non-interactive configuration, if enabled by the UI
+// options or the environment.
+func (ui UI) automation() (Automation, bool) {
+	if ui.Automation.Enabled {
+		return ui.Automation, true
+	}
+	a, err := AutomationFromEnv()
+	if err != nil && a.Enabled {
+		// do not guess with an invalid configuration
+		ui.reportAutomationError(err)
+		a.AssumeYes = false
+		a.Select = SelectNone
+	}
+	return a, a.Enabled
+}
+
+// reportedAutomationErrors were already logged, so that an invalid
+// configuration is only reported once, and not for every prompt.
+var (
+	reportedAutomationErrors   = make(map[string]bool)
+	reportedAutomationErrorsMu sync.Mutex
+)
+
+// reportAutomationError in the configuration from the environment, once.
+func (ui UI) reportAutomationError(err error) {
+	reportedAutomationErrorsMu.Lock()
+	reported := reportedAutomationErrors[err.Error()]
+	reportedAutomationErrors[err.Error()] = true
+	reportedAutomationErrorsMu.Unlock()
+	if !reported {
+		ui.Warningf("[non-interactive] %s: answering no and selecting nothing", err.Error())
+	}
+}
+
+// logDecision made automatically.
+func (ui UI) logDecision(msg string, args ...interface{}) {
+	ui.Infof("[non-interactive] "+msg, args...)
+}
+
+// autoSelect an option according to the policy.
+func (ui UI) autoSelect(title string, options []Option, policy SelectPolicy) (Option, error) {
+	var source string
+	switch policy {
+	case SelectNone:
+		ui.logDecision("%s: no option selected", title)
+		return Option{}, ErrNonInteractive
+	case SelectLocal:
+		source = SourceLocal
+	case SelectOnline:
+		source = SourceOnline
+	}
+	for _, o := range options {
+		if source == "" || o.Source == source {
+			ui.logDecision("%s: selected %q", title, o.Value)
+			return o, nil
+		}
+	}
+	ui.logDecision("%s: no option matching the select policy", title)
+	return Option{}, ErrNonInteractive
+}
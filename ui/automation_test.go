@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIAutomationFromEnv(t *testing.T) {
+	fmt.Println("+ Testing UI/AutomationFromEnv()...")
+	assert := assert.New(t)
+
+	restore := setenv(map[string]string{EnvNonInteractive: "1", EnvAssumeYes: "true", EnvSelectPolicy: "online"})
+	a, err := AutomationFromEnv()
+	assert.Nil(err)
+	assert.Equal(Automation{Enabled: true, AssumeYes: true, Select: SelectOnline}, a)
+	ui := &UI{}
+	assert.True(ui.Accept("Continue?"))
+	restore()
+
+	restore = setenv(map[string]string{EnvNonInteractive: "1", EnvAssumeYes: "", EnvSelectPolicy: "random"})
+	_, err = AutomationFromEnv()
+	assert.NotNil(err)
+	a, auto := ui.automation()
+	assert.True(auto)
+	assert.Equal(SelectNone, a.Select, "invalid configuration should not guess")
+	restore()
+
+	// unparseable values fail closed
+	for _, value := range []string{"yes", "on", "enabled"} {
+		restore = setenv(map[string]string{EnvNonInteractive: value, EnvAssumeYes: "true", EnvSelectPolicy: "first"})
+		a, err = AutomationFromEnv()
+		assert.NotNil(err)
+		assert.Equal(Automation{Enabled: true, Select: SelectNone}, a)
+		a, auto = ui.automation()
+		assert.True(auto, value)
+		assert.False(a.AssumeYes)
+		assert.False(ui.Accept("Continue?"))
+		_, err = ui.GetInput()
+		assert.Equal(ErrNonInteractive, err)
+		restore()
+	}
+
+	// invalid configurations are only logged once
+	logFilename := "../test/automation-testing"
+	logged := &UI{}
+	assert.Nil(logged.getLogger(logFilename))
+	defer os.Remove(logFilename)
+	restore = setenv(map[string]string{EnvNonInteractive: "maybe", EnvAssumeYes: "", EnvSelectPolicy: ""})
+	assert.False(logged.Accept("Continue?"))
+	assert.False(logged.Accept("Really?"))
+	restore()
+	logged.CloseLog()
+	output, err := ioutil.ReadFile(logFilename)
+	assert.Nil(err)
+	assert.Equal(1, strings.Count(string(output), "invalid "+EnvNonInteractive))
+
+	restore = setenv(map[string]string{EnvNonInteractive: "false", EnvAssumeYes: "", EnvSelectPolicy: ""})
+	_, auto = ui.automation()
+	assert.False(auto)
+	restore()
+
+	restore = setenv(map[string]string{EnvNonInteractive: "", EnvAssumeYes: "", EnvSelectPolicy: ""})
+	_, auto = ui.automation()
+	assert.False(auto)
+	restore()
+}
+
+func TestUIAutomation(t *testing.T) {
+	fmt.Println("+ Testing UI/Automation...")
+	assert := assert.New(t)
+	restore := setenv(map[string]string{EnvNonInteractive: "", EnvAssumeYes: "", EnvSelectPolicy: ""})
+	defer restore()
+
+	ui := &UI{Automation: Automation{Enabled: true}}
+	assert.False(ui.Accept("Continue?"))
+	assert.True(ui.AcceptDefault("Continue?", true))
+	ui.Automation.AssumeYes = true
+	assert.True(ui.Accept("Continue?"))
+
+	value, err := ui.UpdateValue("title", "", " old title ", false)
+	assert.Nil(err)
+	assert.Equal("old title", value)
+	value, err = ui.Edit("old")
+	assert.Nil(err)
+	assert.Equal("old", value)
+	_, err = ui.GetInput()
+	assert.Equal(ErrNonInteractive, err)
+	_, err = ui.GetSecret("password")
+	assert.Equal(ErrNonInteractive, err)
+
+	options := []string{ui.Tag("online 1", false), ui.Tag("local", true), ui.Tag("online 2", false)}
+	for _, c := range []struct {
+		policy   SelectPolicy
+		expected string
+	}{
+		{SelectFirst, "online 1"},
+		{SelectLocal, "local"},
+		{SelectOnline, "online 1"},
+	} {
+		ui.Automation.Select = c.policy
+		choice, err := ui.SelectOption("title", "", options, false)
+		assert.Nil(err)
+		assert.Equal(c.expected, choice)
+	}
+	// the policy decides what SelectOptions returns
+	ui.Automation.Select = SelectFirst
+	selected, err := ui.SelectOptions("title", "", options)
+	assert.Nil(err)
+	assert.Equal([]string{"online 1"}, selected)
+	choice, err := ui.Pick("title", options)
+	assert.Nil(err)
+	assert.Equal("online 1", choice)
+
+	ui.Automation.Select = SelectNone
+	_, err = ui.SelectOption("title", "", options, false)
+	assert.Equal(ErrNonInteractive, err)
+	ui.Automation.Select = SelectLocal
+	_, err = ui.SelectOption("title", "", []string{"untagged"}, false)
+	assert.Equal(ErrNonInteractive, err)
+}
@@ -0,0 +1,261 @@
+package ui
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// maxDiffSize is the maximum product of the number of tokens compared;
+// beyond it, texts are considered entirely different.
+const maxDiffSize = 4000000
+
+var wordTokens = regexp.MustCompile(`\s+|\S+`)
+
+// DiffOperation applied to a chunk of text.
+type DiffOperation int
+
+// Diff operations.
+const (
+	DiffEqual DiffOperation = iota
+	DiffDelete
+	DiffInsert
+)
+
+// DiffChunk is a piece of text that was kept, deleted or inserted.
+type DiffChunk struct {
+	Operation DiffOperation
+	Text      string
+}
+
+// DiffWords compares two texts word by word.
+// Whitespace is kept in the chunks, so that concatenating equal and deleted
+// chunks gives the old text, and equal and inserted chunks the new one.
+func DiffWords(oldText, newText string) []DiffChunk {
+	return diffTokens(wordTokens.FindAllString(oldText, -1), wordTokens.FindAllString(newText, -1), "")
+}
+
+// DiffLines compares two texts line by line.
+// Each chunk is a single line, without line return.
+func DiffLines(oldText, newText string) []DiffChunk {
+	var chunks []DiffChunk
+	for _, c := range diffTokens(strings.Split(oldText, "\n"), strings.Split(newText, "\n"), "\n") {
+		for _, line := range strings.Split(c.Text, "\n") {
+			chunks = append(chunks, DiffChunk{c.Operation, line})
+		}
+	}
+	return chunks
+}
+
+// diffTokens finds the longest common subsequence of two lists of tokens, and
+// returns the chunks to go from one to the other. Adjacent tokens with the same
+// operation are merged, joined with separator.
+func diffTokens(a, b []string, separator string) []DiffChunk {
+	var chunks []DiffChunk
+	add := func(op DiffOperation, token string) {
+		if n := len(chunks); n != 0 && chunks[n-1].Operation == op {
+			chunks[n-1].Text += separator + token
+			return
+		}
+		chunks = append(chunks, DiffChunk{op, token})
+	}
+	if len(a)*len(b) > maxDiffSize {
+		for _, t := range a {
+			add(DiffDelete, t)
+		}
+		for _, t := range b {
+			add(DiffInsert, t)
+		}
+		return chunks
+	}
+
+	// lengths[i][j] is the length of the LCS of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			add(DiffEqual, a[i])
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			add(DiffDelete, a[i])
+			i++
+		default:
+			add(DiffInsert, b[j])
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		add(DiffDelete, a[i])
+	}
+	for ; j < len(b); j++ {
+		add(DiffInsert, b[j])
+	}
+	return chunks
+}
+
+// deleted text, in red, or between [- and -] without colours.
+func (ui UI) deleted(text string) string {
+	if !ui.ColorEnabled() {
+		return "[-" + text + "-]"
+	}
+	return ui.Red(text)
+}
+
+// inserted text, in green, or between {+ and +} without colours.
+func (ui UI) inserted(text string) string {
+	if !ui.ColorEnabled() {
+		return "{+" + text + "+}"
+	}
+	return ui.Green(text)
+}
+
+// Diff shows the differences between two texts word by word, deletions in red
+// and insertions in green.
+func (ui UI) Diff(oldText, newText string) string {
+	out := ""
+	for _, c := range DiffWords(oldText, newText) {
+		switch c.Operation {
+		case DiffEqual:
+			out += c.Text
+		case DiffDelete:
+			out += ui.deleted(c.Text)
+		case DiffInsert:
+			out += ui.inserted(c.Text)
+		}
+	}
+	return out
+}
+
+// SideBySide shows two texts in two columns fitting in width, with changed
+// lines highlighted word by word.
+func (ui UI) SideBySide(oldText, newText string, width int) string {
+	gutter := " │ "
+	if ui.theme().ASCII {
+		gutter = " | "
+	}
+	column := (width - runewidth.StringWidth(gutter)) / 2
+	if column < 10 {
+		column = 10
+	}
+
+	// pair deleted and inserted lines
+	type row struct{ left, right string }
+	var rows []row
+	var deleted, inserted []string
+	flush := func() {
+		for i := 0; i < len(deleted) || i < len(inserted); i++ {
+			r := row{}
+			switch {
+			case i < len(deleted) && i < len(inserted):
+				r.left, r.right = ui.changedLine(deleted[i], inserted[i])
+			case i < len(deleted):
+				r.left = ui.deleted(deleted[i])
+			default:
+				r.right = ui.inserted(inserted[i])
+			}
+			rows = append(rows, r)
+		}
+		deleted, inserted = nil, nil
+	}
+	for _, c := range DiffLines(oldText, newText) {
+		switch c.Operation {
+		case DiffEqual:
+			flush()
+			rows = append(rows, row{c.Text, c.Text})
+		case DiffDelete:
+			deleted = append(deleted, c.Text)
+		case DiffInsert:
+			inserted = append(inserted, c.Text)
+		}
+	}
+	flush()
+
+	var lines []string
+	for _, r := range rows {
+		left := wrapStyled(r.left, column)
+		right := wrapStyled(r.right, column)
+		for i := 0; i < len(left) || i < len(right); i++ {
+			l, rr := "", ""
+			if i < len(left) {
+				l = left[i]
+			}
+			if i < len(right) {
+				rr = right[i]
+			}
+			padding := column - runewidth.StringWidth(stripANSI(l))
+			if padding < 0 {
+				padding = 0
+			}
+			lines = append(lines, strings.TrimRight(l+strings.Repeat(" ", padding)+gutter+rr, " "))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// changedLine returns both versions of a changed line, with their
+// differences highlighted.
+func (ui UI) changedLine(oldLine, newLine string) (string, string) {
+	left, right := "", ""
+	for _, c := range DiffWords(oldLine, newLine) {
+		switch c.Operation {
+		case DiffEqual:
+			left += c.Text
+			right += c.Text
+		case DiffDelete:
+			left += ui.deleted(c.Text)
+		case DiffInsert:
+			right += ui.inserted(c.Text)
+		}
+	}
+	return left, right
+}
+
+var ansiCodes = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// stripANSI removes colour codes.
+func stripANSI(s string) string {
+	return ansiCodes.ReplaceAllString(s, "")
+}
+
+// wrapStyled wraps a line that may contain colour codes to width.
+// Lines containing colour codes are wrapped without them, to keep widths
+// right.
+func wrapStyled(line string, width int) []string {
+	plain := stripANSI(line)
+	if runewidth.StringWidth(plain) <= width {
+		return []string{line}
+	}
+	return wrapText(plain, width)
+}
+
+// showDiff between the current value and a candidate, side by side for long
+// fields spanning several lines.
+func (ui UI) showDiff(oldValue, newValue string, longField bool) {
+	if oldValue == newValue {
+		return
+	}
+	if longField && strings.Contains(oldValue+newValue, "\n") {
+		width, _ := terminalSize()
+		fmt.Println(ui.SideBySide(oldValue, newValue, width))
+		return
+	}
+	fmt.Println(ui.Diff(oldValue, newValue))
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIDiff(t *testing.T) {
+	fmt.Println("+ Testing UI/Diff()...")
+	assert := assert.New(t)
+
+	oldText := "The quick brown fox jumps"
+	newText := "The quick red fox leaps"
+	chunks := DiffWords(oldText, newText)
+	// concatenating chunks gives back both texts
+	var before, after string
+	for _, c := range chunks {
+		if c.Operation != DiffInsert {
+			before += c.Text
+		}
+		if c.Operation != DiffDelete {
+			after += c.Text
+		}
+	}
+	assert.Equal(oldText, before)
+	assert.Equal(newText, after)
+
+	ui := &UI{Color: ColorNever}
+	assert.Equal("The quick [-brown-]{+red+} fox [-jumps-]{+leaps+}", ui.Diff(oldText, newText))
+	assert.Equal("same", ui.Diff("same", "same"))
+	assert.Equal("{+new+}", ui.Diff("", "new"))
+	ui.Color = ColorAlways
+	assert.Equal("The quick "+ui.Red("brown")+ui.Green("red")+" fox "+ui.Red("jumps")+ui.Green("leaps"), ui.Diff(oldText, newText))
+
+	lines := DiffLines("one\ntwo\nthree", "one\n2\nthree\nfour")
+	assert.Equal([]DiffChunk{
+		{DiffEqual, "one"},
+		{DiffDelete, "two"},
+		{DiffInsert, "2"},
+		{DiffEqual, "three"},
+		{DiffInsert, "four"},
+	}, lines)
+}
+
+func TestUISideBySide(t *testing.T) {
+	fmt.Println("+ Testing UI/SideBySide()...")
+	assert := assert.New(t)
+	ui := &UI{Color: ColorNever}
+
+	out := ui.SideBySide("title\nold line\nremoved", "title\nnew line\nadded\nextra", 43)
+	expected := []string{
+		"title                │ title",
+		"[-old-] line         │ {+new+} line",
+		"[-removed-]          │ {+added+}",
+		"                     │ {+extra+}",
+	}
+	assert.Equal(strings.Join(expected, "\n"), out)
+
+	// long lines are wrapped
+	out = ui.SideBySide("a long line that will need wrapping", "a long line", 43)
+	expected = []string{
+		"a long line[- that   │ a long line",
+		"will need wrapping-] │",
+	}
+	assert.Equal(strings.Join(expected, "\n"), out)
+}
+
+func TestUISelectOptionDiff(t *testing.T) {
+	fmt.Println("+ Testing UI/SelectOption() with differences...")
+	assert := assert.New(t)
+	ui := &UI{Color: ColorNever}
+
+	ui.SetInput(strings.NewReader("2\n"))
+	choice, err := ui.SelectOption("Title", "", []string{ui.Tag("Les Miserables", true), ui.Tag("Les Misérables", false)}, false)
+	assert.Nil(err)
+	assert.Equal("Les Misérables", choice)
+}
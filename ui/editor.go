@@ -0,0 +1,230 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Environment variables defining the editor command, VISUAL first.
+const (
+	EnvVisual = "VISUAL"
+	EnvEditor = "EDITOR"
+)
+
+// FallbackEditors are tried in order if neither $VISUAL nor $EDITOR can be
+// used, and only on a terminal.
+var FallbackEditors = []string{"nano", "vim", "vi"}
+
+var (
+	// ErrNoEditor is returned when no editor could be run.
+	ErrNoEditor = errors.New("no editor found, set $EDITOR")
+	// ErrEditUnchanged is returned by EditWithOptions when the text was not
+	// modified, so that callers can abort.
+	ErrEditUnchanged = errors.New("text unchanged")
+)
+
+const defaultComment = "#"
+
+// EditOptions configures EditWithOptions.
+type EditOptions struct {
+	// Extension of the temporary file, such as "md" or "yaml", so that the
+	// editor can pick the right syntax highlighting.
+	Extension string
+	// Template is edited instead of the value if it is empty.
+	Template string
+	// Help is added as comment lines after the text, to guide the user.
+	Help string
+	// Comment is the prefix of lines removed from the edited text, like git
+	// commit messages. Comments are kept if it is empty, unless Help is set,
+	// in which case # is used.
+	Comment string
+}
+
+// comment prefix, if comments are stripped.
+func (o EditOptions) comment() string {
+	if o.Comment == "" && o.Help != "" {
+		return defaultComment
+	}
+	return o.Comment
+}
+
+// splitShellWords splits a command line into words, as a shell would:
+// words are separated by whitespace, unless quoted with ' or ", and \ escapes
+// the next character, except between single quotes.
+func splitShellWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord, escaped := false, false
+	var quote rune
+	for _, c := range line {
+		switch {
+		case escaped:
+			word.WriteRune(c)
+			escaped = false
+		case quote == '\'':
+			if c == '\'' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\\':
+			escaped, inWord = true, true
+		case quote == '"':
+			if c == '"' {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote, inWord = c, true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("trailing backslash in %q", line)
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unterminated quote in %q", line)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// editors returns the commands to try, from the environment first.
+func (ui UI) editors() [][]string {
+	var commands [][]string
+	for _, env := range []string{EnvVisual, EnvEditor} {
+		words, err := splitShellWords(os.Getenv(env))
+		if err != nil {
+			ui.Warningf("invalid $%s: %s", env, err.Error())
+			continue
+		}
+		if len(words) != 0 {
+			commands = append(commands, words)
+		}
+	}
+	// without a terminal, only run editors the user chose
+	if IsInteractive() {
+		if len(commands) == 0 {
+			ui.Warning("$EDITOR not set, falling back to " + strings.Join(FallbackEditors, ", "))
+		}
+		for _, e := range FallbackEditors {
+			commands = append(commands, []string{e})
+		}
+	}
+	return commands
+}
+
+// runEditor on a file, trying every available editor until one can be found.
+func (ui UI) runEditor(filename string) error {
+	for _, command := range ui.editors() {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			ui.Debugf("editor %s not found", command[0])
+			continue
+		}
+		cmd := exec.Command(command[0], append(command[1:], filename)...)
+		cmd.Stdin = os.Stdin
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		return cmd.Run()
+	}
+	return ErrNoEditor
+}
+
+// stripComments removes lines starting with prefix.
+func stripComments(text, prefix string) string {
+	if prefix == "" {
+		return text
+	}
+	var lines []string
+	for _, l := range strings.Split(text, "\n") {
+		if !strings.HasPrefix(l, prefix) {
+			lines = append(lines, l)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Edit long value using external $EDITOR
+func (ui *UI) Edit(oldValue string) (string, error) {
+	newValue, err := ui.EditWithOptions(oldValue, EditOptions{})
+	if err == ErrEditUnchanged {
+		return newValue, nil
+	}
+	return newValue, err
+}
+
+// EditWithOptions edits a value with an external editor.
+// The edited text is trimmed, and ErrEditUnchanged is returned along with it
+// if it is the same as the original text.
+func (ui *UI) EditWithOptions(oldValue string, options EditOptions) (string, error) {
+	if _, auto := ui.automation(); auto {
+		ui.logDecision("edition skipped, kept current value")
+		return oldValue, nil
+	}
+	text := oldValue
+	if text == "" {
+		text = options.Template
+	}
+	content := text
+	if options.Help != "" {
+		comment := options.comment()
+		content = strings.TrimRight(content, "\n") + "\n\n"
+		for _, l := range strings.Split(strings.TrimRight(options.Help, "\n"), "\n") {
+			content += strings.TrimRight(comment+" "+l, " ") + "\n"
+		}
+	}
+
+	// create temp file
+	pattern := "edit"
+	if options.Extension != "" {
+		pattern += "*." + strings.TrimPrefix(options.Extension, ".")
+	}
+	tmpfile, err := ioutil.TempFile("", pattern)
+	if err != nil {
+		return oldValue, err
+	}
+	// clean up, even if interrupted
+	defer tmpfile.Close()
+	defer os.Remove(tmpfile.Name())
+	defer RegisterCleanup(func() {
+		os.Remove(tmpfile.Name())
+	})()
+
+	// write input string inside
+	if _, err := tmpfile.Write([]byte(content)); err != nil {
+		return oldValue, err
+	}
+	if err := tmpfile.Close(); err != nil {
+		return oldValue, err
+	}
+
+	if err := ui.runEditor(tmpfile.Name()); err != nil {
+		return oldValue, err
+	}
+
+	// read file back, set output string
+	newContent, err := ioutil.ReadFile(tmpfile.Name())
+	if err != nil {
+		return oldValue, err
+	}
+	newValue := strings.TrimSpace(stripComments(string(newContent), options.comment()))
+	if newValue == strings.TrimSpace(stripComments(text, options.comment())) {
+		return newValue, ErrEditUnchanged
+	}
+	return newValue, nil
+}
@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUISplitShellWords(t *testing.T) {
+	fmt.Println("+ Testing UI/splitShellWords()...")
+	assert := assert.New(t)
+
+	for line, expected := range map[string][]string{
+		"":                              nil,
+		"vim":                           {"vim"},
+		"  code   --wait ":              {"code", "--wait"},
+		`"/opt/my editor/bin/ed" -n`:    {"/opt/my editor/bin/ed", "-n"},
+		`emacs -nw --eval '(setq x 1)'`: {"emacs", "-nw", "--eval", "(setq x 1)"},
+		`my\ editor "say \"hi\"" ''`:    {"my editor", `say "hi"`, ""},
+	} {
+		words, err := splitShellWords(line)
+		assert.Nil(err, line)
+		assert.Equal(expected, words, line)
+	}
+	for _, line := range []string{`vim "file`, `vim 'file`, `vim \`} {
+		_, err := splitShellWords(line)
+		assert.NotNil(err, line)
+	}
+}
+
+func TestUIEditWithOptions(t *testing.T) {
+	fmt.Println("+ Testing UI/EditWithOptions()...")
+	assert := assert.New(t)
+	ui := &UI{}
+
+	dir, err := ioutil.TempDir("", "editor")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	// fake editor, with arguments, saving the name of the file it edits
+	log := filepath.Join(dir, "edited file")
+	script := filepath.Join(dir, "fake editor")
+	fakeCommand := "#!/bin/sh\n[ \"$1\" = \"--wait\" ] || exit 1\necho \"$2\" > \"" + log + "\"\n[ -z \"$MODE\" ] || sed -i 's/old/new/' \"$2\"\n"
+	assert.Nil(ioutil.WriteFile(script, []byte(fakeCommand), 0777))
+	restore := setenv(map[string]string{EnvVisual: "", EnvEditor: `"` + script + `" --wait`, "MODE": ""})
+	defer restore()
+
+	// unchanged
+	output, err := ui.EditWithOptions("old value", EditOptions{Extension: "md"})
+	assert.Equal(ErrEditUnchanged, err)
+	assert.Equal("old value", output)
+	edited, err := ioutil.ReadFile(log)
+	assert.Nil(err)
+	assert.Equal(".md", filepath.Ext(string(edited[:len(edited)-1])))
+	// Edit does not care
+	output, err = ui.Edit("old value")
+	assert.Nil(err)
+	assert.Equal("old value", output)
+
+	// help comments are stripped, template used for empty values
+	os.Setenv("MODE", "replace")
+	output, err = ui.EditWithOptions("", EditOptions{Template: "old template", Help: "Replace old with new.\nLines starting with # are ignored."})
+	assert.Nil(err)
+	assert.Equal("new template", output)
+	output, err = ui.EditWithOptions("old\n; comment", EditOptions{Comment: ";"})
+	assert.Nil(err)
+	assert.Equal("new", output)
+
+	// $VISUAL comes first
+	os.Setenv(EnvVisual, "false")
+	_, err = ui.EditWithOptions("old", EditOptions{})
+	assert.NotNil(err)
+
+	// no editor
+	os.Setenv(EnvVisual, "")
+	os.Setenv(EnvEditor, "does-not-exist")
+	_, err = ui.EditWithOptions("old", EditOptions{})
+	assert.Equal(ErrNoEditor, err)
+}
@@ -0,0 +1,311 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+const (
+	formBack     = "<"
+	formBackHelp = "(%s to go back)"
+	formRequired = "A value is required."
+	formReview   = "Review"
+	formConfirm  = "[C]onfirm, edit field [1-%d], or [A]bort: "
+	secretMask   = "********"
+)
+
+// FieldType defines how a form field is asked and checked.
+type FieldType int
+
+const (
+	// FieldText is a line of text.
+	FieldText FieldType = iota
+	// FieldInt is an integer.
+	FieldInt
+	// FieldFloat is a number.
+	FieldFloat
+	// FieldDate is a date, in Field.Layout or YYYY-MM-DD.
+	FieldDate
+	// FieldBool is a yes/no answer, stored as "true" or "false".
+	FieldBool
+	// FieldChoice is one of Field.Options.
+	FieldChoice
+	// FieldSecret is read without echo, and masked in the review.
+	FieldSecret
+	// FieldLongText is edited with an external editor. It is not possible to
+	// go back from it.
+	FieldLongText
+)
+
+// Field of a Form.
+type Field struct {
+	// Name of the field in the result.
+	Name string
+	// Label shown to the user, Name if empty.
+	Label string
+	Type  FieldType
+	// Default value, used for empty input.
+	Default string
+	// Required fields cannot be left empty.
+	Required bool
+	// Validate checks the input, after the checks of the field type.
+	Validate Validator
+	// Options of a FieldChoice.
+	Options []string
+	// Layout of a FieldDate.
+	Layout string
+	// VisibleIf decides if the field is asked, given the values of the
+	// previous fields. The field is always asked if nil.
+	VisibleIf func(values map[string]string) bool
+}
+
+// label shown to the user.
+func (f Field) label() string {
+	if f.Label == "" {
+		return f.Name
+	}
+	return f.Label
+}
+
+// visible checks if the field is asked.
+func (f Field) visible(values map[string]string) bool {
+	return f.VisibleIf == nil || f.VisibleIf(values)
+}
+
+// dateLayout of a FieldDate.
+func (f Field) dateLayout() string {
+	if f.Layout == "" {
+		return "2006-01-02"
+	}
+	return f.Layout
+}
+
+// validator for the field type and the field validator.
+func (f Field) validator() Validator {
+	var typed Validator
+	switch f.Type {
+	case FieldInt:
+		typed = func(input string) error {
+			if _, err := strconv.Atoi(input); err != nil {
+				return fmt.Errorf("%q is not an integer", input)
+			}
+			return nil
+		}
+	case FieldFloat:
+		typed = func(input string) error {
+			if _, err := strconv.ParseFloat(input, 64); err != nil {
+				return fmt.Errorf("%q is not a number", input)
+			}
+			return nil
+		}
+	case FieldDate:
+		typed = ValidateDate(f.dateLayout())
+	}
+	return ValidateAll(typed, f.Validate)
+}
+
+// Form is a sequence of fields asked in order, with the possibility to go back
+// to the previous field, and to review the values before confirming them.
+// It only relies on UserInterface, so it works with any implementation, and
+// translates messages and answers if it is also a Translator.
+type Form struct {
+	Title  string
+	Fields []Field
+}
+
+// NewForm with a title.
+func NewForm(title string) *Form {
+	return &Form{Title: title}
+}
+
+// Add a field to the form.
+func (f *Form) Add(field Field) *Form {
+	f.Fields = append(f.Fields, field)
+	return f
+}
+
+// Run the form, and return the values of the visible fields by name.
+// In non-interactive mode, default values are used.
+func (f *Form) Run(ui UserInterface) (map[string]string, error) {
+	ui.SubPart(f.Title)
+	values := make(map[string]string)
+	if err := f.fill(ui, values, 0, false); err != nil {
+		return nil, err
+	}
+
+	for errs := 0; errs <= maxErrors; {
+		visible := f.review(ui, values)
+		ui.Choice(translate(ui, formConfirm), len(visible))
+		choice, err := ui.GetInput()
+		if err == ErrNonInteractive {
+			return values, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case isShortcut(choice, formConfirm, translate(ui, formConfirm), "C"):
+			return values, nil
+		case isShortcut(choice, formConfirm, translate(ui, formConfirm), "A"):
+			return nil, errors.New(userAborted)
+		}
+		index, err := strconv.Atoi(choice)
+		if err != nil || index < 1 || index > len(visible) {
+			ui.Warning(translate(ui, invalidChoice))
+			errs++
+			continue
+		}
+		// ask the field again, then the fields it made visible
+		if err := f.fill(ui, values, visible[index-1], true); err != nil {
+			return nil, err
+		}
+	}
+	ui.Warning(translate(ui, tooManyErrors))
+	return nil, errors.New(invalidChoice)
+}
+
+// fill asks the visible fields from start, or only the field at start and the
+// visible fields without value if onlyMissing is set.
+// Values of hidden fields are removed.
+func (f *Form) fill(ui UserInterface, values map[string]string, start int, onlyMissing bool) error {
+	var asked []int
+	for i := start; i < len(f.Fields); {
+		field := f.Fields[i]
+		if !field.visible(values) {
+			delete(values, field.Name)
+			i++
+			continue
+		}
+		if _, ok := values[field.Name]; ok && onlyMissing && i != start {
+			i++
+			continue
+		}
+		value, back, err := f.ask(ui, field, values, len(asked) != 0)
+		if err != nil {
+			return err
+		}
+		if back {
+			i = asked[len(asked)-1]
+			asked = asked[:len(asked)-1]
+			continue
+		}
+		values[field.Name] = value
+		asked = append(asked, i)
+		i++
+	}
+	return nil
+}
+
+// ask a field until its value is valid. It returns true if the user wants to
+// go back to the previous field.
+func (f *Form) ask(ui UserInterface, field Field, values map[string]string, canGoBack bool) (string, bool, error) {
+	current, ok := values[field.Name]
+	if !ok {
+		current = field.Default
+	}
+	yes, no := yesNo(ui)
+	question := field.label()
+	if field.Type == FieldChoice {
+		var options string
+		for i, o := range field.Options {
+			options += fmt.Sprintf("%d. %s\n", i+1, o)
+		}
+		ui.Display(options)
+		question += fmt.Sprintf(" [1-%d]", len(field.Options))
+	}
+	if field.Type == FieldDate {
+		question += fmt.Sprintf(" [%s]", field.dateLayout())
+	}
+	if field.Type == FieldBool {
+		question += fmt.Sprintf(" [%s/%s]", yes[0], no[0])
+	}
+	if current != "" && field.Type != FieldSecret {
+		question += fmt.Sprintf(" (%s)", current)
+	}
+	if canGoBack && field.Type != FieldLongText {
+		question += " " + fmt.Sprintf(translate(ui, formBackHelp), formBack)
+	}
+	validate := field.validator()
+
+	for errs := 0; errs <= maxErrors; errs++ {
+		var input string
+		var err error
+		switch field.Type {
+		case FieldLongText:
+			ui.Choice("%s\n", question)
+			input, err = ui.Edit(current)
+		case FieldSecret:
+			input, err = ui.GetSecret(question)
+		default:
+			ui.Choice("%s: ", question)
+			input, err = ui.GetInput()
+		}
+		if err == ErrNonInteractive && (current != "" || !field.Required) {
+			return current, false, nil
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if input == formBack && canGoBack && field.Type != FieldLongText {
+			return "", true, nil
+		}
+		if input == "" {
+			input = current
+		}
+
+		switch {
+		case input == "" && field.Required:
+			err = errors.New(translate(ui, formRequired))
+		case input == "":
+			return "", false, nil
+		case field.Type == FieldBool:
+			switch {
+			case isAnswer(input, yes) || input == "true":
+				return "true", false, nil
+			case isAnswer(input, no) || input == "false":
+				return "false", false, nil
+			}
+			err = errors.New(translate(ui, invalidChoice))
+		case field.Type == FieldChoice:
+			if index, convErr := strconv.Atoi(input); convErr == nil && index >= 1 && index <= len(field.Options) {
+				input = field.Options[index-1]
+			}
+			err = errors.New(translate(ui, invalidChoice))
+			for _, o := range field.Options {
+				if o == input {
+					err = validate(input)
+					break
+				}
+			}
+		default:
+			err = validate(input)
+		}
+		if err == nil {
+			return input, false, nil
+		}
+		ui.Warning(err.Error())
+	}
+	ui.Warning(translate(ui, tooManyErrors))
+	return "", false, errors.New(invalidChoice)
+}
+
+// review shows the values of the visible fields, and returns their indexes.
+func (f *Form) review(ui UserInterface, values map[string]string) []int {
+	ui.SubTitle(translate(ui, formReview))
+	var visible []int
+	var review string
+	for i, field := range f.Fields {
+		value, ok := values[field.Name]
+		if !ok {
+			continue
+		}
+		if field.Type == FieldSecret && value != "" {
+			value = secretMask
+		}
+		visible = append(visible, i)
+		review += fmt.Sprintf("%d. %s: %s\n", len(visible), field.label(), value)
+	}
+	ui.Display(review)
+	return visible
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testForm() *Form {
+	return NewForm("New book").
+		Add(Field{Name: "title", Label: "Title", Required: true}).
+		Add(Field{Name: "year", Type: FieldInt, Default: "2017", Validate: ValidateInt(1000, 2100)}).
+		Add(Field{Name: "format", Type: FieldChoice, Options: []string{"epub", "mobi", "pdf"}}).
+		Add(Field{Name: "drm", Label: "DRM", Type: FieldBool, VisibleIf: func(values map[string]string) bool {
+			return values["format"] != "pdf"
+		}}).
+		Add(Field{Name: "read", Type: FieldDate})
+}
+
+func TestUIForm(t *testing.T) {
+	fmt.Println("+ Testing UI/Form...")
+	assert := assert.New(t)
+	ui := &UI{Color: ColorNever}
+
+	// errors, defaults, going back, conditional fields
+	input := []string{
+		"",           // title is required
+		"Dune",       // title
+		"3000",       // year out of range
+		"",           // default year
+		"4",          // invalid format
+		"<",          // back to year
+		"1965",       // year
+		"2",          // mobi
+		"maybe",      // invalid answer
+		"n",          // no DRM
+		"2017-13-01", // invalid date
+		"2017-10-01", // read date
+		"3",          // edit format
+		"pdf",        // pdf: DRM is hidden
+		"C",          // confirm
+	}
+	ui.SetInput(strings.NewReader(strings.Join(input, "\n") + "\n"))
+	values, err := testForm().Run(ui)
+	assert.Nil(err)
+	assert.Equal(map[string]string{"title": "Dune", "year": "1965", "format": "pdf", "read": "2017-10-01"}, values)
+
+	// making a field visible again asks for it during review
+	input = []string{"Dune", "", "pdf", "", "3", "1", "<", "2", "y", "c"}
+	ui.SetInput(strings.NewReader(strings.Join(input, "\n") + "\n"))
+	values, err = testForm().Run(ui)
+	assert.Nil(err)
+	assert.Equal(map[string]string{"title": "Dune", "year": "2017", "format": "mobi", "drm": "true", "read": ""}, values)
+
+	// output and answers go through the UserInterface, even when wrapped
+	var output bytes.Buffer
+	fr := &UI{Color: ColorNever, Language: "fr", Pager: Pager{Output: &output}}
+	fr.SetInput(strings.NewReader("Dune\n\n2\noui\n\nc\n"))
+	values, err = testForm().Run(NewRecorder(fr, ioutil.Discard))
+	assert.Nil(err)
+	assert.Equal("true", values["drm"])
+	assert.Equal("1. epub\n2. mobi\n3. pdf\n1. Title: Dune\n2. year: 2017\n3. format: mobi\n4. DRM: true\n5. read: \n", output.String())
+
+	// abort
+	ui.SetInput(strings.NewReader("Dune\n\n1\nn\n\nA\n"))
+	_, err = testForm().Run(ui)
+	assert.NotNil(err)
+
+	// non-interactive mode: defaults are used, but required fields are needed
+	ui = &UI{Automation: Automation{Enabled: true}}
+	form := NewForm("Defaults").Add(Field{Name: "year", Default: "2017"}).Add(Field{Name: "tags"})
+	values, err = form.Run(ui)
+	assert.Nil(err)
+	assert.Equal(map[string]string{"year": "2017", "tags": ""}, values)
+	_, err = testForm().Run(ui)
+	assert.Equal(ErrNonInteractive, err)
+}
@@ -0,0 +1,249 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Catalog of translated messages for a language.
+// Messages are identified by their English text, like with gettext.
+// Shortcut keys are the letters between brackets in messages, such as [E]dit:
+// translations must keep shortcuts in the same order.
+type Catalog struct {
+	Language string
+	// Messages translated, by English message.
+	Messages map[string]string
+	// Plurals are the translated forms of messages depending on a count, by
+	// English singular message.
+	Plurals map[string][]string
+	// PluralForm returns the index of the plural form to use for n.
+	PluralForm func(n int) int
+	// Answers recognized by Accept and its variants, the first one of each
+	// list being shown to the user.
+	Yes, No, Always, Never []string
+}
+
+var (
+	// CatalogEnglish is the default catalog, with the original messages and
+	// YesAnswers, NoAnswers, AlwaysAnswers and NeverAnswers.
+	CatalogEnglish = Catalog{
+		Language: "en",
+		PluralForm: func(n int) int {
+			if n == 1 {
+				return 0
+			}
+			return 1
+		},
+	}
+	// CatalogFrench translates messages in French.
+	CatalogFrench = Catalog{
+		Language: "fr",
+		Messages: map[string]string{
+			editOrKeep:                          "[M]odifier ou [G]arder la valeur actuelle : ",
+			enterNewValue:                       "Entrer la nouvelle valeur : ",
+			invalidChoice:                       "Choix invalide.",
+			emptyValue:                          "Valeur vide.",
+			notConfirmed:                        "Saisie manuelle non confirmée, nouvel essai.",
+			tooManyErrors:                       "Trop d'erreurs, abandon.",
+			userAborted:                         "Abandon par l'utilisateur.",
+			modifying:                           "Modification de %s",
+			currentValue:                        "Valeur actuelle : %s\n",
+			confirm:                             "Confirmer",
+			confirmValue:                        "Confirmer : %s",
+			selectNoOption:                      "Laisser [V]ide, [M]odifier manuellement, ou [A]bandonner : ",
+			selectOneOption:                     "Choisir [1], laisser [V]ide, [M]odifier manuellement, ou [A]bandonner : ",
+			selectOptions:                       "Choisir une option [1-%d], laisser [V]ide, [M]odifier manuellement, ou [A]bandonner : ",
+			toggleOptions:                       "Basculer les options [1-%d] (ex. 1,3,5-7), choisir [tout] ou [rien], [C]onfirmer ou [A]bandonner : ",
+			invalidSelection:                    "Sélection invalide.",
+			secretsDoNotMatch:                   "Les saisies ne correspondent pas, nouvel essai.",
+			rememberHelp:                        "(%s : toujours, %s : jamais pendant cette session)",
+			formRequired:                        "Une valeur est requise.",
+			formReview:                          "Vérification",
+			formConfirm:                         "[C]onfirmer, modifier le champ [1-%d], ou [A]bandonner : ",
+			formBackHelp:                        "(%s pour revenir en arrière)",
+			editRecordHelp:                      "Les lignes commençant par # sont ignorées.\nEnregistrer le fichier sans modification pour abandonner.",
+			pagerHelp:                           "Espace/PgSuiv : page suivante, Entrée/↓ : ligne suivante, b/PgPréc : page précédente, ↑ : ligne précédente, g/G : début/fin, q : quitter",
+			selectorHelp:                        "↑/↓ PgPréc/PgSuiv pour se déplacer, taper pour filtrer, Entrée pour choisir, Échap pour abandonner",
+			selectionAll:                        "tout",
+			selectionNone:                       "rien",
+			"Could not cleanly close log file.": "Impossible de fermer proprement le fichier de log.",
+			"Could not cleanly close decision journal.": "Impossible de fermer proprement le journal des décisions.",
+		},
+		Plurals: map[string][]string{
+			optionsSelected: {"%d option sélectionnée : %s", "%d options sélectionnées : %s"},
+		},
+		PluralForm: func(n int) int {
+			if n <= 1 {
+				return 0
+			}
+			return 1
+		},
+		Yes:    []string{"o", "oui"},
+		No:     []string{"n", "non"},
+		Always: []string{"t", "toujours"},
+		Never:  []string{"j", "jamais"},
+	}
+
+	// Catalogs available by language.
+	Catalogs = map[string]*Catalog{
+		CatalogEnglish.Language: &CatalogEnglish,
+		CatalogFrench.Language:  &CatalogFrench,
+	}
+)
+
+// Messages that are only used translated.
+const (
+	modifying       = "Modifying %s"
+	currentValue    = "Current value: %s\n"
+	confirm         = "Confirm"
+	confirmValue    = "Confirm: %s"
+	selectNoOption  = "Leave [B]lank, [E]dit manually, or [A]bort: "
+	selectOneOption = "Choose [1], leave [B]lank, [E]dit manually, or [A]bort: "
+	selectOptions   = "Choose option [1-%d], leave [B]lank, [E]dit manually, or [A]bort: "
+	optionsSelected = "%d option selected: %s"
+)
+
+// LanguageFromEnv returns the language of messages, from LC_ALL, LC_MESSAGES
+// or LANG, in that order, such as "fr" for "fr_FR.UTF-8".
+// It returns "en" if none is set.
+func LanguageFromEnv() string {
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		value := os.Getenv(env)
+		if value == "" {
+			continue
+		}
+		parts := strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+			return r == '_' || r == '.' || r == '@' || r == '-'
+		})
+		if len(parts) == 0 || parts[0] == "c" || parts[0] == "posix" {
+			return CatalogEnglish.Language
+		}
+		return parts[0]
+	}
+	return CatalogEnglish.Language
+}
+
+// catalog for the language of the UI, or the environment, falling back to
+// English.
+func (ui UI) catalog() *Catalog {
+	language := ui.Language
+	if language == "" {
+		language = LanguageFromEnv()
+	}
+	if c, ok := Catalogs[language]; ok {
+		return c
+	}
+	return &CatalogEnglish
+}
+
+// T translates a message, and formats it with args if there are any.
+func (ui UI) T(msg string, args ...interface{}) string {
+	if translated, ok := ui.catalog().Messages[msg]; ok {
+		msg = translated
+	}
+	return sprintf(msg, args...)
+}
+
+// TN translates a message depending on n, and formats it with args.
+// singular is the English message for n == 1, and identifies the message.
+func (ui UI) TN(singular, plural string, n int, args ...interface{}) string {
+	c := ui.catalog()
+	msg := plural
+	if n == 1 {
+		msg = singular
+	}
+	if forms, ok := c.Plurals[singular]; ok && c.PluralForm != nil {
+		if i := c.PluralForm(n); i >= 0 && i < len(forms) {
+			msg = forms[i]
+		}
+	}
+	return sprintf(msg, args...)
+}
+
+// translate a message with ui if it is a Translator.
+func translate(ui UserInterface, msg string) string {
+	if t, ok := ui.(Translator); ok {
+		return t.T(msg)
+	}
+	return msg
+}
+
+// translatePlural a message depending on n with ui if it is a Translator.
+func translatePlural(ui UserInterface, singular, plural string, n int) string {
+	if t, ok := ui.(Translator); ok {
+		return t.TN(singular, plural, n)
+	}
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// yesNo returns the answers to yes/no questions of ui if it is a
+// Translator, or YesAnswers and NoAnswers.
+func yesNo(ui UserInterface) (yes, no []string) {
+	if t, ok := ui.(Translator); ok {
+		return t.YesNo()
+	}
+	return YesAnswers, NoAnswers
+}
+
+// sprintf formats msg with args if there are any.
+func sprintf(msg string, args ...interface{}) string {
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
+
+var shortcutKeys = regexp.MustCompile(`\[(\pL)\]`)
+
+// shortcuts of a translated message, in order.
+func shortcuts(msg string) []string {
+	var keys []string
+	for _, m := range shortcutKeys.FindAllStringSubmatch(msg, -1) {
+		keys = append(keys, m[1])
+	}
+	return keys
+}
+
+// isShortcut checks if input is the shortcut key of a message, given its
+// English message and key, and its translation. Case is ignored.
+func isShortcut(input, msg, translated, key string) bool {
+	keys := shortcuts(translated)
+	for i, k := range shortcuts(msg) {
+		if strings.EqualFold(k, key) && i < len(keys) {
+			key = keys[i]
+			break
+		}
+	}
+	return strings.EqualFold(input, key)
+}
+
+// isShortcut checks if input is the translated shortcut key of a message,
+// given its English key.
+func (ui UI) isShortcut(input, msg, key string) bool {
+	return isShortcut(input, msg, ui.T(msg), key)
+}
+
+// answers of the catalog, falling back to YesAnswers, NoAnswers, AlwaysAnswers
+// and NeverAnswers.
+func (ui UI) answers() (yes, no, always, never []string) {
+	c := ui.catalog()
+	yes, no, always, never = c.Yes, c.No, c.Always, c.Never
+	if len(yes) == 0 || len(no) == 0 {
+		yes, no = YesAnswers, NoAnswers
+	}
+	if len(always) == 0 || len(never) == 0 {
+		always, never = AlwaysAnswers, NeverAnswers
+	}
+	return
+}
+
+// YesNo returns the answers to yes/no questions in the language of the UI.
+func (ui UI) YesNo() (yes, no []string) {
+	yes, no, _, _ = ui.answers()
+	return yes, no
+}
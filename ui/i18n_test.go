@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	// messages are checked in English, whatever the locale of the tests
+	os.Setenv("LC_ALL", "C")
+	os.Exit(m.Run())
+}
+
+func TestUILanguageFromEnv(t *testing.T) {
+	fmt.Println("+ Testing UI/LanguageFromEnv()...")
+	assert := assert.New(t)
+
+	for env, expected := range map[[3]string]string{
+		{"", "", ""}:                      "en",
+		{"", "", "fr_FR.UTF-8"}:           "fr",
+		{"", "de_DE", "fr_FR.UTF-8"}:      "de",
+		{"C", "de_DE", "fr_FR.UTF-8"}:     "en",
+		{"fr_BE@euro", "", "en_US.UTF-8"}: "fr",
+		{"", "POSIX", "fr_FR.UTF-8"}:      "en",
+		{"", "", "pt-BR"}:                 "pt",
+		{"", "", "_"}:                     "en",
+	} {
+		restore := setenv(map[string]string{"LC_ALL": env[0], "LC_MESSAGES": env[1], "LANG": env[2]})
+		assert.Equal(expected, LanguageFromEnv(), env)
+		restore()
+	}
+
+	restore := setenv(map[string]string{"LC_ALL": "fr_FR.UTF-8"})
+	defer restore()
+	assert.Equal("fr", UI{}.catalog().Language)
+	assert.Equal("en", UI{Language: "en"}.catalog().Language)
+	assert.Equal("en", UI{Language: "de"}.catalog().Language)
+}
+
+// wrappers of UI must translate messages with it.
+var (
+	_ Translator = (*UI)(nil)
+	_ Translator = (*Recorder)(nil)
+	_ Translator = (*Replayer)(nil)
+)
+
+// untranslated is a UserInterface that does not translate messages.
+type untranslated struct {
+	UserInterface
+}
+
+func TestUITranslate(t *testing.T) {
+	fmt.Println("+ Testing UI/translate()...")
+	assert := assert.New(t)
+	fr := &UI{Language: "fr"}
+
+	assert.Equal("Choix invalide.", translate(fr, invalidChoice))
+	assert.Equal("Choix invalide.", translate(NewRecorder(fr, ioutil.Discard), invalidChoice))
+	assert.Equal("Invalid choice.", translate(untranslated{fr}, invalidChoice))
+	assert.Equal("%d options sélectionnées : %s", translatePlural(&Replayer{UserInterface: fr}, optionsSelected, "%d options selected: %s", 2))
+	assert.Equal("%d options selected: %s", translatePlural(untranslated{fr}, optionsSelected, "%d options selected: %s", 2))
+
+	// wrappers format messages
+	assert.Equal("Modification de titre", NewRecorder(fr, ioutil.Discard).T(modifying, "titre"))
+	assert.Equal("Modifying titre", NewRecorder(untranslated{fr}, ioutil.Discard).T(modifying, "titre"))
+}
+
+func TestUITranslations(t *testing.T) {
+	fmt.Println("+ Testing UI/T()...")
+	assert := assert.New(t)
+	en := UI{Language: "en"}
+	fr := UI{Language: "fr"}
+
+	assert.Equal("Invalid choice.", en.T(invalidChoice))
+	assert.Equal("Choix invalide.", fr.T(invalidChoice))
+	assert.Equal("Modification de titre", fr.T(modifying, "titre"))
+	assert.Equal("unknown message", fr.T("unknown message"))
+
+	for n, expected := range map[int][2]string{
+		0: {"0 options selected: ", "0 option sélectionnée : "},
+		1: {"1 option selected: a", "1 option sélectionnée : a"},
+		2: {"2 options selected: a, b", "2 options sélectionnées : a, b"},
+	} {
+		values := []string{"a", "b"}[:n]
+		assert.Equal(expected[0], en.TN(optionsSelected, "%d options selected: %s", n, n, strings.Join(values, ", ")))
+		assert.Equal(expected[1], fr.TN(optionsSelected, "%d options selected: %s", n, n, strings.Join(values, ", ")))
+	}
+
+	// every translation keeps the shortcuts and formatting verbs
+	for msg, translated := range CatalogFrench.Messages {
+		assert.Equal(len(shortcuts(msg)), len(shortcuts(translated)), msg)
+		assert.Equal(strings.Count(msg, "%"), strings.Count(translated, "%"), msg)
+	}
+	assert.Equal([]string{"B", "E", "A"}, shortcuts(selectNoOption))
+	assert.True(fr.isShortcut("v", selectNoOption, "B"))
+	assert.True(fr.isShortcut("M", selectNoOption, "E"))
+	assert.False(fr.isShortcut("b", selectNoOption, "B"))
+	assert.True(en.isShortcut("b", selectNoOption, "B"))
+}
+
+func TestUITranslatedPrompts(t *testing.T) {
+	fmt.Println("+ Testing UI/T() in prompts...")
+	assert := assert.New(t)
+	ui := &UI{Language: "fr", Color: ColorNever}
+
+	ui.SetInput(strings.NewReader("b\nv\n"))
+	choice, err := ui.SelectOption("Titre", "", []string{"Dune"}, false)
+	assert.Nil(err)
+	assert.Equal("", choice)
+
+	ui.SetInput(strings.NewReader("m\nDune Messiah\noui\n"))
+	choice, err = ui.SelectOption("Titre", "", []string{"Dune"}, false)
+	assert.Nil(err)
+	assert.Equal("Dune Messiah", choice)
+
+	ui.SetInput(strings.NewReader("g\n"))
+	choice, err = ui.UpdateValue("titre", "", "Dune", false)
+	assert.Nil(err)
+	assert.Equal("Dune", choice)
+
+	ui.SetInput(strings.NewReader("tout\nc\no\n"))
+	selected, err := ui.SelectOptions("Tags", "", []string{"sf", "fantasy"})
+	assert.Nil(err)
+	assert.Equal([]string{"sf", "fantasy"}, selected)
+
+	// answers come from the catalog only
+	ui.SetInput(strings.NewReader("y\no\n"))
+	assert.False(ui.Accept("Continuer ?"))
+	assert.True(ui.Accept("Continuer ?"))
+	en := &UI{Language: "en"}
+	en.SetInput(strings.NewReader("Y\nyes\nn\n"))
+	assert.True(en.Accept("Continue?"))
+	assert.True(en.Accept("Continue?"))
+	assert.False(en.Accept("Continue?"))
+
+	ui.SetInput(strings.NewReader("t\n"))
+	answer, err := ui.AcceptWithOptions("Continuer ?", AcceptOptions{Remember: true})
+	assert.Nil(err)
+	assert.True(answer)
+	answer, err = ui.AcceptWithOptions("Continuer ?", AcceptOptions{Remember: true})
+	assert.Nil(err)
+	assert.True(answer, "answer should be remembered")
+
+	form := NewForm("Livre").Add(Field{Name: "lu", Type: FieldBool})
+	ui.SetInput(strings.NewReader("non\nc\n"))
+	values, err := form.Run(ui)
+	assert.Nil(err)
+	assert.Equal(map[string]string{"lu": "false"}, values)
+}
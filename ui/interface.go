@@ -1,19 +1,29 @@
 package ui
 
+import "io"
+
 // UserInterface deals with user input, output and logging.
 type UserInterface interface {
 	// input
 	GetInput() (string, error)
+	GetSecret(string) (string, error)
+	GetNewSecret(string) (string, error)
 	Accept(string) bool
+	AcceptDefault(string, bool) bool
 	UpdateValue(string, string, string, bool) (string, error)
 	SelectOption(string, string, []string, bool) (string, error)
+	SelectFrom(string, string, []Option, bool) (Option, error)
+	SelectOptions(string, string, []string) ([]string, error)
 	Edit(string) (string, error)
+	EditWithOptions(string, EditOptions) (string, error)
+	EditStruct(interface{}, RecordFormat, func(interface{}) error) error
 	// output
 	Title(string, ...interface{})
 	SubTitle(string, ...interface{})
 	SubPart(string, ...interface{})
 	Choice(string, ...interface{})
 	Display(string)
+	DisplayReader(io.Reader) error
 	Tag(string, bool) string
 	// log
 	InitLogger(string) error
@@ -27,3 +37,14 @@ type UserInterface interface {
 	Debug(string)
 	Debugf(string, ...interface{})
 }
+
+// Translator is implemented by user interfaces translating their messages,
+// such as UI. It is optional: messages are not translated by other
+// implementations of UserInterface.
+type Translator interface {
+	T(string, ...interface{}) string
+	TN(string, string, int, ...interface{}) string
+	// YesNo returns the answers to yes/no questions, the first one of each
+	// list being shown to the user.
+	YesNo() (yes, no []string)
+}
@@ -0,0 +1,309 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// journalSuffix replaces the extension of the log file to get the default
+// journal path.
+const journalSuffix = "-decisions.jsonl"
+
+// DecisionMethod is how a value was decided.
+type DecisionMethod string
+
+// Decision methods.
+const (
+	// DecisionSelected is a candidate chosen by the user.
+	DecisionSelected DecisionMethod = "selected"
+	// DecisionTyped is a value typed by the user.
+	DecisionTyped DecisionMethod = "typed"
+	// DecisionEdited is a value edited with an external editor.
+	DecisionEdited DecisionMethod = "edited"
+	// DecisionKept is the current value, kept by the user.
+	DecisionKept DecisionMethod = "kept"
+	// DecisionBlank is an empty value chosen by the user.
+	DecisionBlank DecisionMethod = "blank"
+	// DecisionAborted means the user gave up.
+	DecisionAborted DecisionMethod = "aborted"
+	// DecisionAutomatic is a value chosen in non-interactive mode.
+	DecisionAutomatic DecisionMethod = "automatic"
+	// DecisionReplayed is a value applied from a previous journal.
+	DecisionReplayed DecisionMethod = "replayed"
+)
+
+// Decision taken by the user about the value of a field.
+type Decision struct {
+	Time       time.Time      `json:"time"`
+	Field      string         `json:"field"`
+	OldValue   string         `json:"old_value,omitempty"`
+	Candidates []string       `json:"candidates,omitempty"`
+	Value      string         `json:"value"`
+	Method     DecisionMethod `json:"method"`
+}
+
+// Journal of decisions, written as JSON lines.
+type Journal struct {
+	mu   sync.Mutex
+	w    io.Writer
+	file *os.File
+}
+
+// OpenJournal appends decisions to a file, creating it if necessary.
+func OpenJournal(path string) (*Journal, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
+	if err != nil {
+		return nil, err
+	}
+	return &Journal{w: f, file: f}, nil
+}
+
+// NewJournal writes decisions to w.
+func NewJournal(w io.Writer) *Journal {
+	return &Journal{w: w}
+}
+
+// Record a decision, timestamped now if it has no time.
+func (j *Journal) Record(d Decision) error {
+	if d.Time.IsZero() {
+		d.Time = time.Now()
+	}
+	data, err := json.Marshal(d)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.w.Write(append(data, '\n'))
+	return err
+}
+
+// Close the journal file, if it was opened with OpenJournal.
+func (j *Journal) Close() error {
+	if j.file == nil {
+		return nil
+	}
+	return j.file.Close()
+}
+
+// ReadJournal reads decisions written by a Journal.
+func ReadJournal(r io.Reader) ([]Decision, error) {
+	var decisions []Decision
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var d Decision
+		if err := json.Unmarshal(scanner.Bytes(), &d); err != nil {
+			return decisions, fmt.Errorf("journal line %d: %w", line, err)
+		}
+		decisions = append(decisions, d)
+	}
+	return decisions, scanner.Err()
+}
+
+// ReadJournalFile reads decisions from a journal file.
+func ReadJournalFile(path string) ([]Decision, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadJournal(f)
+}
+
+// DecisionFilter selects decisions. Empty criteria match everything.
+type DecisionFilter struct {
+	Field   string
+	Methods []DecisionMethod
+	Since   time.Time
+	Until   time.Time
+}
+
+// match checks if a decision matches all criteria.
+func (f DecisionFilter) match(d Decision) bool {
+	if f.Field != "" && d.Field != f.Field {
+		return false
+	}
+	if !f.Since.IsZero() && d.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && d.Time.After(f.Until) {
+		return false
+	}
+	if len(f.Methods) == 0 {
+		return true
+	}
+	for _, m := range f.Methods {
+		if d.Method == m {
+			return true
+		}
+	}
+	return false
+}
+
+// FilterDecisions returns the decisions matching the filter, in order.
+func FilterDecisions(decisions []Decision, filter DecisionFilter) []Decision {
+	var out []Decision
+	for _, d := range decisions {
+		if filter.match(d) {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// decisionReplay applies decisions from a journal, in order for each field.
+type decisionReplay struct {
+	mu      sync.Mutex
+	byField map[string][]Decision
+}
+
+// next decision for a field, removing it from the replay.
+func (r *decisionReplay) next(field string) (Decision, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queue := r.byField[field]
+	if len(queue) == 0 {
+		return Decision{}, false
+	}
+	r.byField[field] = queue[1:]
+	return queue[0], true
+}
+
+// EnableJournal records the decisions taken with UpdateValue and SelectOption
+// in path, or next to the log file if path is empty.
+func (ui *UI) EnableJournal(path string) error {
+	if path == "" {
+		if ui.logFile == nil {
+			return errors.New("no log file to write the journal next to")
+		}
+		name := ui.logFile.Name()
+		path = strings.TrimSuffix(name, filepath.Ext(name)) + journalSuffix
+	}
+	j, err := OpenJournal(path)
+	if err != nil {
+		return err
+	}
+	ui.SetJournal(j)
+	return nil
+}
+
+// SetJournal records decisions to j, or stops recording them if nil.
+// The previous journal is closed.
+func (ui *UI) SetJournal(j *Journal) {
+	if ui.journal != nil && ui.journal != j {
+		if err := ui.journal.Close(); err != nil {
+			ui.Error(ui.T("Could not cleanly close decision journal."))
+		}
+	}
+	ui.journal = j
+}
+
+// ReplayDecisions re-applies previous decisions instead of asking the user,
+// for the fields they were taken for, and in the same order.
+// Aborted decisions make the prompt fail again, and kept decisions keep the
+// current value of this run. Values kept or picked automatically among
+// options must still be options, or the prompt fails.
+func (ui *UI) ReplayDecisions(decisions []Decision) {
+	r := &decisionReplay{byField: make(map[string][]Decision)}
+	for _, d := range decisions {
+		r.byField[d.Field] = append(r.byField[d.Field], d)
+	}
+	ui.replay = r
+}
+
+// replayed returns the next decision to replay for a field, if any.
+func (ui UI) replayed(field string) (Decision, bool) {
+	if ui.replay == nil {
+		return Decision{}, false
+	}
+	d, ok := ui.replay.next(field)
+	if ok {
+		ui.Infof("[replay] %s: %s %q", field, d.Method, d.Value)
+	}
+	return d, ok
+}
+
+// decide records a decision in the journal, if enabled.
+func (ui UI) decide(field, oldValue string, candidates []string, value string, method DecisionMethod) {
+	if ui.journal == nil {
+		return
+	}
+	d := Decision{Field: field, OldValue: oldValue, Candidates: candidates, Value: value, Method: method}
+	if err := ui.journal.Record(d); err != nil {
+		ui.Warningf("could not record decision: %s", err.Error())
+	}
+}
+
+// keepsCurrent checks if a decision kept the value of its run, rather than
+// choosing one that can be applied to another run.
+func keepsCurrent(d Decision) bool {
+	return d.Method == DecisionKept || d.Method == DecisionAutomatic
+}
+
+// replayedOption is the option chosen by a replayed decision, keeping its
+// source and metadata if it is still one of the options.
+// Kept decisions keep the current option of this run, if there is one.
+// Kept and automatic decisions were taken among the options of their run, so
+// their value must still be one of the options.
+func replayedOption(d Decision, options []Option) (Option, error) {
+	switch d.Method {
+	case DecisionAborted:
+		return Option{}, errors.New(userAborted)
+	case DecisionBlank:
+		return Option{}, nil
+	case DecisionKept:
+		if current, ok := currentOption(options); ok {
+			return current, nil
+		}
+	}
+	for _, o := range options {
+		if o.Value == d.Value {
+			return o, nil
+		}
+	}
+	if keepsCurrent(d) {
+		return Option{}, fmt.Errorf("replayed value %q is not one of the options", d.Value)
+	}
+	return Option{Value: d.Value}, nil
+}
+
+// replayMethod records aborted decisions as such when they are replayed.
+func replayMethod(d Decision) DecisionMethod {
+	if d.Method == DecisionAborted {
+		return DecisionAborted
+	}
+	return DecisionReplayed
+}
+
+// editMethod depending on how the value was input.
+func editMethod(value string, longField bool) DecisionMethod {
+	switch {
+	case value == "":
+		return DecisionBlank
+	case longField:
+		return DecisionEdited
+	default:
+		return DecisionTyped
+	}
+}
+
+// optionValues of options.
+func optionValues(options []Option) []string {
+	var values []string
+	for _, o := range options {
+		values = append(values, o.Value)
+	}
+	return values
+}
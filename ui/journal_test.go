@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIJournal(t *testing.T) {
+	fmt.Println("+ Testing UI/Journal...")
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	ui := &UI{Color: ColorNever}
+	ui.SetJournal(NewJournal(&buf))
+	options := []Option{{Value: "Dune", Source: SourceLocal}, {Value: "Dune Messiah", Source: SourceOnline}}
+
+	ui.SetInput(strings.NewReader("2\n"))
+	_, err := ui.SelectFrom("title", "", options, false)
+	assert.Nil(err)
+	ui.SetInput(strings.NewReader("1\n"))
+	_, err = ui.SelectFrom("title", "", options, false)
+	assert.Nil(err)
+	ui.SetInput(strings.NewReader("b\n"))
+	_, err = ui.SelectFrom("title", "", options, false)
+	assert.Nil(err)
+	ui.SetInput(strings.NewReader("a\n"))
+	_, err = ui.SelectFrom("title", "", options, false)
+	assert.NotNil(err)
+	ui.SetInput(strings.NewReader("e\nFrank Herbert\ny\n"))
+	_, err = ui.UpdateValue("author", "", "Herbert", false)
+	assert.Nil(err)
+	ui.SetInput(strings.NewReader("k\n"))
+	_, err = ui.UpdateValue("year", "", "1965", false)
+	assert.Nil(err)
+
+	decisions, err := ReadJournal(&buf)
+	assert.Nil(err)
+	assert.Equal(6, len(decisions))
+	var methods []DecisionMethod
+	for _, d := range decisions {
+		methods = append(methods, d.Method)
+		assert.False(d.Time.IsZero())
+	}
+	assert.Equal([]DecisionMethod{DecisionSelected, DecisionKept, DecisionBlank, DecisionAborted, DecisionTyped, DecisionKept}, methods)
+	assert.Equal(Decision{Time: decisions[0].Time, Field: "title", OldValue: "Dune", Candidates: []string{"Dune", "Dune Messiah"}, Value: "Dune Messiah", Method: DecisionSelected}, decisions[0])
+	assert.Equal("Herbert", decisions[4].OldValue)
+	assert.Equal("Frank Herbert", decisions[4].Value)
+
+	// queries
+	assert.Equal(4, len(FilterDecisions(decisions, DecisionFilter{Field: "title"})))
+	assert.Equal(2, len(FilterDecisions(decisions, DecisionFilter{Methods: []DecisionMethod{DecisionKept}})))
+	assert.Equal(0, len(FilterDecisions(decisions, DecisionFilter{Since: time.Now().Add(time.Hour)})))
+	assert.Equal(6, len(FilterDecisions(decisions, DecisionFilter{Until: time.Now()})))
+
+	_, err = ReadJournal(strings.NewReader("{}\nnot json\n"))
+	var syntaxErr *json.SyntaxError
+	assert.True(errors.As(err, &syntaxErr), "errors are wrapped")
+}
+
+func TestUIReplayDecisions(t *testing.T) {
+	fmt.Println("+ Testing UI/ReplayDecisions()...")
+	assert := assert.New(t)
+
+	var buf bytes.Buffer
+	ui := &UI{Color: ColorNever}
+	ui.SetJournal(NewJournal(&buf))
+	ui.ReplayDecisions([]Decision{
+		{Field: "title", Value: "Dune Messiah", Method: DecisionSelected},
+		{Field: "author", Value: "Frank Herbert", Method: DecisionTyped},
+		{Field: "title", Method: DecisionBlank},
+		{Field: "title", Method: DecisionAborted},
+	})
+	// no input is needed while there are decisions to replay
+	ui.SetInput(strings.NewReader(""))
+	options := []Option{{Value: "Dune", Source: SourceLocal}, {Value: "Dune Messiah", Source: SourceOnline}}
+
+	choice, err := ui.SelectFrom("title", "", options, false)
+	assert.Nil(err)
+	assert.Equal(options[1], choice)
+	value, err := ui.UpdateValue("author", "", "Herbert", false)
+	assert.Nil(err)
+	assert.Equal("Frank Herbert", value)
+	choice, err = ui.SelectFrom("title", "", options, false)
+	assert.Nil(err)
+	assert.Equal(Option{}, choice)
+	_, err = ui.SelectFrom("title", "", options, false)
+	assert.NotNil(err)
+
+	// then the user is asked again
+	ui.SetInput(strings.NewReader("1\n"))
+	choice, err = ui.SelectFrom("title", "", options, false)
+	assert.Nil(err)
+	assert.Equal(options[0], choice)
+
+	decisions, err := ReadJournal(&buf)
+	assert.Nil(err)
+	var methods []DecisionMethod
+	for _, d := range decisions {
+		methods = append(methods, d.Method)
+	}
+	assert.Equal([]DecisionMethod{DecisionReplayed, DecisionReplayed, DecisionReplayed, DecisionAborted, DecisionKept}, methods)
+}
+
+func TestUIReplayKeptDecisions(t *testing.T) {
+	fmt.Println("+ Testing UI/ReplayDecisions() with kept values...")
+	assert := assert.New(t)
+
+	ui := &UI{Color: ColorNever}
+	ui.SetInput(strings.NewReader(""))
+	ui.ReplayDecisions([]Decision{
+		{Field: "year", OldValue: "1965", Value: "1965", Method: DecisionKept},
+		{Field: "title", OldValue: "Dune", Value: "Dune", Method: DecisionKept},
+		{Field: "year", OldValue: "1966", Value: "1966", Method: DecisionAutomatic},
+	})
+	// the current values of this run are kept, not the ones of the journal
+	value, err := ui.UpdateValue("year", "", "1969", false)
+	assert.Nil(err)
+	assert.Equal("1969", value)
+	options := []Option{{Value: "Dune Messiah", Source: SourceLocal}, {Value: "Dune", Source: SourceOnline}}
+	choice, err := ui.SelectFrom("title", "", options, false)
+	assert.Nil(err)
+	assert.Equal(options[0], choice)
+	value, err = ui.UpdateValue("year", "", " 1976 ", false)
+	assert.Nil(err)
+	assert.Equal("1976", value)
+
+	// automatic picks replay their value if it is still an option
+	ui.ReplayDecisions([]Decision{
+		{Field: "title", Value: "Dune", Method: DecisionAutomatic},
+		{Field: "title", Value: "Dune", Method: DecisionAutomatic},
+		{Field: "title", Value: "Dune", Method: DecisionKept},
+	})
+	options = []Option{{Value: "Dune Messiah", Source: SourceLocal}, {Value: "Dune", Source: SourceOnline}}
+	choice, err = ui.SelectFrom("title", "", options, false)
+	assert.Nil(err)
+	assert.Equal(options[1], choice)
+	_, err = ui.SelectFrom("title", "", []Option{{Value: "Children of Dune", Source: SourceOnline}}, false)
+	assert.NotNil(err, "not blanked")
+	_, err = ui.SelectFrom("title", "", []Option{{Value: "Children of Dune", Source: SourceOnline}}, false)
+	assert.NotNil(err, "nothing to keep")
+}
+
+func TestUIEnableJournal(t *testing.T) {
+	fmt.Println("+ Testing UI/EnableJournal()...")
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "journal")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	ui := &UI{Color: ColorNever}
+	assert.NotNil(ui.EnableJournal(""), "no log file to write next to")
+	assert.Nil(ui.getLogger(filepath.Join(dir, "run.log")))
+	assert.Nil(ui.EnableJournal(""))
+	ui.SetInput(strings.NewReader("k\n"))
+	_, err = ui.UpdateValue("year", "", "1965", false)
+	assert.Nil(err)
+	// replacing the journal closes the previous one
+	previous := ui.journal
+	assert.Nil(ui.EnableJournal(filepath.Join(dir, "other.jsonl")))
+	assert.NotNil(previous.Record(Decision{Field: "closed"}))
+	ui.CloseLog()
+
+	decisions, err := ReadJournalFile(filepath.Join(dir, "run"+journalSuffix))
+	assert.Nil(err)
+	assert.Equal(1, len(decisions))
+	assert.Equal("1965", decisions[0].Value)
+}
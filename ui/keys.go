@@ -0,0 +1,130 @@
+package ui
+
+import (
+	"bufio"
+	"unicode/utf8"
+)
+
+// key pressed on a terminal in raw mode.
+type key int
+
+const (
+	keyRune key = iota
+	keyUnknown
+	keyEnter
+	keyEscape
+	keyTab
+	keyBackspace
+	keyDelete
+	keyUp
+	keyDown
+	keyLeft
+	keyRight
+	keyHome
+	keyEnd
+	keyPageUp
+	keyPageDown
+	keyInterrupt
+	keyEOF
+	keyKillToEnd
+	keyKillToStart
+	keyKillWord
+)
+
+// keyPress is a key, and the rune typed if it is keyRune.
+type keyPress struct {
+	key  key
+	char rune
+}
+
+// control characters
+var controlKeys = map[byte]key{
+	0x01: keyHome,        // ctrl-a
+	0x02: keyLeft,        // ctrl-b
+	0x03: keyInterrupt,   // ctrl-c
+	0x04: keyEOF,         // ctrl-d
+	0x05: keyEnd,         // ctrl-e
+	0x06: keyRight,       // ctrl-f
+	0x08: keyBackspace,   // ctrl-h
+	0x09: keyTab,         // tab
+	0x0a: keyEnter,       // \n
+	0x0b: keyKillToEnd,   // ctrl-k
+	0x0d: keyEnter,       // \r
+	0x0e: keyDown,        // ctrl-n
+	0x10: keyUp,          // ctrl-p
+	0x15: keyKillToStart, // ctrl-u
+	0x17: keyKillWord,    // ctrl-w
+	0x7f: keyBackspace,   // backspace
+}
+
+// escape sequences, without the leading ESC
+var escapeSequences = map[string]key{
+	"[A":  keyUp,
+	"[B":  keyDown,
+	"[C":  keyRight,
+	"[D":  keyLeft,
+	"[H":  keyHome,
+	"[F":  keyEnd,
+	"OA":  keyUp,
+	"OB":  keyDown,
+	"OC":  keyRight,
+	"OD":  keyLeft,
+	"OH":  keyHome,
+	"OF":  keyEnd,
+	"[1~": keyHome,
+	"[7~": keyHome,
+	"[4~": keyEnd,
+	"[8~": keyEnd,
+	"[3~": keyDelete,
+	"[5~": keyPageUp,
+	"[6~": keyPageDown,
+}
+
+// readKey reads a key press from a terminal in raw mode.
+// An escape character is considered as the Escape key if nothing else was
+// received with it.
+func readKey(r *bufio.Reader) (keyPress, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return keyPress{}, err
+	}
+	if b == 0x1b {
+		if r.Buffered() == 0 {
+			return keyPress{key: keyEscape}, nil
+		}
+		// read the sequence, until its final byte
+		sequence := ""
+		for r.Buffered() > 0 {
+			c, err := r.ReadByte()
+			if err != nil {
+				return keyPress{}, err
+			}
+			sequence += string(c)
+			if len(sequence) > 1 && (c >= 'A' && c <= 'Z' || c == '~') {
+				break
+			}
+		}
+		if k, ok := escapeSequences[sequence]; ok {
+			return keyPress{key: k}, nil
+		}
+		return keyPress{key: keyUnknown}, nil
+	}
+	if k, ok := controlKeys[b]; ok {
+		return keyPress{key: k}, nil
+	}
+	if b < 0x20 {
+		return keyPress{key: keyUnknown}, nil
+	}
+	if b < utf8.RuneSelf {
+		return keyPress{key: keyRune, char: rune(b)}, nil
+	}
+	// multi-byte rune
+	if err := r.UnreadByte(); err != nil {
+		return keyPress{}, err
+	}
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return keyPress{}, err
+	}
+	return keyPress{key: keyRune, char: c}, nil
+}
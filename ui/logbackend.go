@@ -0,0 +1,397 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/op/go-logging"
+)
+
+// LogTarget is where logs are sent. Targets can be combined.
+type LogTarget int
+
+// Log targets.
+const (
+	// LogToFile writes logs to the file given to InitLogger.
+	LogToFile LogTarget = 1 << iota
+	// LogToJournald sends logs to the systemd journal.
+	LogToJournald
+	// LogToSyslog sends logs to a syslog server, as defined in RFC 5424.
+	LogToSyslog
+)
+
+const (
+	// DefaultJournalSocket is where journald listens for native messages.
+	DefaultJournalSocket = "/run/systemd/journal/socket"
+	// DefaultSyslogAddress is the local syslog socket.
+	DefaultSyslogAddress = "/dev/log"
+	// SyslogUser is the facility of user-level messages.
+	SyslogUser = 1
+	// SyslogDaemon is the facility of system daemons.
+	SyslogDaemon = 3
+	// SyslogLocal0 is the first facility for local use, up to 23 for local7.
+	SyslogLocal0 = 16
+
+	// syslogEnterpriseID is the private enterprise number reserved for
+	// documentation, for the structured data of syslog messages.
+	syslogEnterpriseID = 32473
+)
+
+// LogOptions configures where InitLogger sends logs.
+type LogOptions struct {
+	// Targets of logs, only the log file if zero.
+	Targets LogTarget
+	// Identifier of the program in journald and syslog, the name of the
+	// executable if empty.
+	Identifier string
+	// Level is the minimum level sent to journald and syslog, such as
+	// "WARNING", everything if empty.
+	Level string
+	// Fields added to every message sent to journald and syslog.
+	// Names must be upper case letters, digits and underscores, starting with
+	// a letter, and cannot be MESSAGE, PRIORITY, SYSLOG_IDENTIFIER or
+	// LOG_MODULE.
+	Fields map[string]string
+	// JournalSocket is DefaultJournalSocket if empty.
+	JournalSocket string
+	// SyslogNetwork is "unix" or "udp", "unix" if empty.
+	SyslogNetwork string
+	// SyslogAddress is DefaultSyslogAddress if empty, or host:port for udp.
+	SyslogAddress string
+	// SyslogFacility is SyslogUser if zero.
+	SyslogFacility int
+}
+
+// identifier of the program, from the options or the executable name.
+func (o LogOptions) identifier() string {
+	if o.Identifier != "" {
+		return o.Identifier
+	}
+	return filepath.Base(os.Args[0])
+}
+
+// backends for journald and syslog, as selected by the options.
+func (o LogOptions) backends() ([]logging.Backend, []*datagramConn, error) {
+	var backends []logging.Backend
+	var conns []*datagramConn
+	if o.Targets&LogToJournald != 0 {
+		j, err := NewJournaldBackend(o.JournalSocket, o.identifier(), o.Fields)
+		if err != nil {
+			return nil, conns, err
+		}
+		backends = append(backends, j)
+		conns = append(conns, j.conn)
+	}
+	if o.Targets&LogToSyslog != 0 {
+		s, err := NewSyslogBackend(o.SyslogNetwork, o.SyslogAddress, o.identifier(), o.SyslogFacility, o.Fields)
+		if err != nil {
+			return nil, conns, err
+		}
+		backends = append(backends, s)
+		conns = append(conns, s.conn)
+	}
+	if o.Level != "" {
+		level, err := logging.LogLevel(o.Level)
+		if err != nil {
+			return nil, conns, err
+		}
+		for i, b := range backends {
+			leveled := logging.AddModuleLevel(b)
+			leveled.SetLevel(level, "")
+			backends[i] = leveled
+		}
+	}
+	return backends, conns, nil
+}
+
+// severity of a level, as defined by syslog and used by journald.
+func severity(level logging.Level) int {
+	switch level {
+	case logging.CRITICAL:
+		return 2
+	case logging.ERROR:
+		return 3
+	case logging.WARNING:
+		return 4
+	case logging.NOTICE:
+		return 5
+	case logging.INFO:
+		return 6
+	}
+	return 7
+}
+
+// datagramConn is a datagram socket, dialled again if sending fails.
+type datagramConn struct {
+	mu      sync.Mutex
+	network string
+	address string
+	conn    net.Conn
+}
+
+// dialDatagram to a unix or udp address.
+func dialDatagram(network, address string) (*datagramConn, error) {
+	c := &datagramConn{network: network, address: address}
+	if err := c.dial(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *datagramConn) dial() error {
+	conn, err := net.Dial(c.network, c.address)
+	if err != nil {
+		return err
+	}
+	c.conn = conn
+	return nil
+}
+
+// send a datagram, dialling again once if it fails.
+func (c *datagramConn) send(data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		if _, err := c.conn.Write(data); err == nil {
+			return nil
+		} else if isMessageTooLong(err) {
+			return err
+		}
+		c.conn.Close()
+		c.conn = nil
+	}
+	if err := c.dial(); err != nil {
+		return err
+	}
+	_, err := c.conn.Write(data)
+	return err
+}
+
+// Close the socket.
+func (c *datagramConn) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	err := c.conn.Close()
+	c.conn = nil
+	return err
+}
+
+// isMessageTooLong checks if a datagram was too large to be sent.
+func isMessageTooLong(err error) bool {
+	var errno syscall.Errno
+	return errors.As(err, &errno) && (errno == syscall.EMSGSIZE || errno == syscall.ENOBUFS)
+}
+
+var journalFieldName = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// journalReservedFields are set by JournaldBackend for every message.
+var journalReservedFields = map[string]bool{
+	"MESSAGE":           true,
+	"PRIORITY":          true,
+	"SYSLOG_IDENTIFIER": true,
+	"LOG_MODULE":        true,
+}
+
+// JournaldBackend is a logging.Backend sending messages to journald with its
+// native protocol, with the priority, identifier and module of every message
+// as structured fields.
+type JournaldBackend struct {
+	identifier string
+	fields     map[string]string
+	conn       *datagramConn
+}
+
+// NewJournaldBackend connects to the journald socket, DefaultJournalSocket if
+// empty. fields are added to every message.
+func NewJournaldBackend(socket, identifier string, fields map[string]string) (*JournaldBackend, error) {
+	for name := range fields {
+		if !journalFieldName.MatchString(name) {
+			return nil, fmt.Errorf("invalid journal field name %q", name)
+		}
+		if journalReservedFields[name] {
+			return nil, fmt.Errorf("reserved journal field name %q", name)
+		}
+	}
+	if socket == "" {
+		socket = DefaultJournalSocket
+	}
+	conn, err := dialDatagram("unixgram", socket)
+	if err != nil {
+		return nil, err
+	}
+	return &JournaldBackend{identifier: identifier, fields: fields, conn: conn}, nil
+}
+
+// Log sends a record to journald.
+func (j *JournaldBackend) Log(level logging.Level, calldepth int, rec *logging.Record) error {
+	fields := map[string]string{
+		"MESSAGE":           rec.Message(),
+		"PRIORITY":          fmt.Sprint(severity(level)),
+		"SYSLOG_IDENTIFIER": j.identifier,
+		"LOG_MODULE":        rec.Module,
+	}
+	for name, value := range j.fields {
+		fields[name] = value
+	}
+	data := journalMessage(fields)
+	err := j.conn.send(data)
+	if isMessageTooLong(err) {
+		return j.sendFile(data)
+	}
+	return err
+}
+
+// sendFile passes a message too large for a datagram as a file descriptor,
+// as supported by journald.
+func (j *JournaldBackend) sendFile(data []byte) error {
+	f, err := ioutil.TempFile("/dev/shm", "journal")
+	if err != nil {
+		if f, err = ioutil.TempFile("", "journal"); err != nil {
+			return err
+		}
+	}
+	defer f.Close()
+	os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		return err
+	}
+	j.conn.mu.Lock()
+	defer j.conn.mu.Unlock()
+	conn, ok := j.conn.conn.(*net.UnixConn)
+	if !ok {
+		return errors.New("journal socket is not connected")
+	}
+	_, _, err = conn.WriteMsgUnix(nil, syscall.UnixRights(int(f.Fd())), nil)
+	return err
+}
+
+// journalMessage encodes fields with the journald native protocol, sorted
+// by name. Values with newlines are prefixed by their length.
+func journalMessage(fields map[string]string) []byte {
+	var names []string
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var buf bytes.Buffer
+	for _, name := range names {
+		value := fields[name]
+		buf.WriteString(name)
+		if strings.Contains(value, "\n") {
+			buf.WriteByte('\n')
+			binary.Write(&buf, binary.LittleEndian, uint64(len(value)))
+		} else {
+			buf.WriteByte('=')
+		}
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// SyslogBackend is a logging.Backend sending RFC 5424 messages to a syslog
+// server, over a unix or udp socket.
+type SyslogBackend struct {
+	identifier string
+	hostname   string
+	facility   int
+	data       string
+	conn       *datagramConn
+}
+
+// NewSyslogBackend connects to a syslog server. network is "unix" or "udp",
+// address is DefaultSyslogAddress if empty, facility is SyslogUser if zero.
+// fields are added to every message as structured data.
+func NewSyslogBackend(network, address, identifier string, facility int, fields map[string]string) (*SyslogBackend, error) {
+	switch network {
+	case "", "unix", "unixgram":
+		network = "unixgram"
+		if address == "" {
+			address = DefaultSyslogAddress
+		}
+	case "udp", "udp4", "udp6":
+	default:
+		return nil, fmt.Errorf("unsupported syslog network %q", network)
+	}
+	if facility == 0 {
+		facility = SyslogUser
+	}
+	if facility < 0 || facility > 23 {
+		return nil, fmt.Errorf("invalid syslog facility %d", facility)
+	}
+	conn, err := dialDatagram(network, address)
+	if err != nil {
+		return nil, err
+	}
+	hostname, _ := os.Hostname()
+	return &SyslogBackend{
+		identifier: syslogName(identifier, 48),
+		hostname:   syslogName(hostname, 255),
+		facility:   facility,
+		data:       syslogData(fields),
+		conn:       conn,
+	}, nil
+}
+
+// Log sends a record to the syslog server.
+func (s *SyslogBackend) Log(level logging.Level, calldepth int, rec *logging.Record) error {
+	return s.conn.send([]byte(s.message(level, rec.Time, rec.Message())))
+}
+
+// message formatted as defined by RFC 5424.
+func (s *SyslogBackend) message(level logging.Level, t time.Time, msg string) string {
+	return fmt.Sprintf("<%d>1 %s %s %s %d - %s %s",
+		s.facility*8+severity(level),
+		t.Format("2006-01-02T15:04:05.000000Z07:00"),
+		s.hostname, s.identifier, os.Getpid(), s.data, msg)
+}
+
+// syslogName keeps printable ASCII characters, up to max, or returns "-".
+func syslogName(name string, max int) string {
+	name = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, name)
+	if len(name) > max {
+		name = name[:max]
+	}
+	if name == "" {
+		return "-"
+	}
+	return name
+}
+
+// syslogData encodes fields as structured data, or "-" if there are none.
+func syslogData(fields map[string]string) string {
+	if len(fields) == 0 {
+		return "-"
+	}
+	var names []string
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	escape := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `]`, `\]`)
+	data := fmt.Sprintf("[fields@%d", syslogEnterpriseID)
+	for _, name := range names {
+		data += fmt.Sprintf(" %s=\"%s\"", syslogName(strings.NewReplacer("=", "", "]", "", `"`, "").Replace(name), 32), escape.Replace(fields[name]))
+	}
+	return data + "]"
+}
@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/op/go-logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// listen for datagrams, returning a function to read the next one.
+func listen(t *testing.T, network, address string) (net.PacketConn, func() string) {
+	conn, err := net.ListenPacket(network, address)
+	require.Nil(t, err)
+	return conn, func() string {
+		buf := make([]byte, 65536)
+		conn.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			return ""
+		}
+		return string(buf[:n])
+	}
+}
+
+func TestUIJournalMessage(t *testing.T) {
+	fmt.Println("+ Testing UI/journalMessage()...")
+	assert := assert.New(t)
+	msg := journalMessage(map[string]string{"PRIORITY": "6", "MESSAGE": "two\nlines"})
+	assert.Equal("MESSAGE\n\x09\x00\x00\x00\x00\x00\x00\x00two\nlines\nPRIORITY=6\n", string(msg))
+}
+
+func TestUIJournaldBackend(t *testing.T) {
+	fmt.Println("+ Testing UI/JournaldBackend...")
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "journald")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "socket")
+	conn, read := listen(t, "unixgram", socket)
+	defer conn.Close()
+
+	for _, name := range []string{"lower", "1DIGIT", "_TRUSTED", "MESSAGE", "PRIORITY", "SYSLOG_IDENTIFIER"} {
+		_, err = NewJournaldBackend(socket, "test", map[string]string{name: "x"})
+		assert.NotNil(err, name)
+	}
+	_, err = NewJournaldBackend(filepath.Join(dir, "missing"), "test", nil)
+	assert.NotNil(err)
+
+	ui := &UI{Log: LogOptions{Targets: LogToJournald, JournalSocket: socket, Identifier: "helpers", Level: "INFO", Fields: map[string]string{"BOOK": "Dune"}}}
+	require.Nil(t, ui.InitLogger("journald-test"))
+	defer ui.CloseLog()
+	assert.Nil(ui.logFile, "no log file")
+
+	ui.Debug("not sent")
+	ui.Warning("Warning")
+	assert.Equal("BOOK=Dune\nLOG_MODULE=journald-test\nMESSAGE=Warning\nPRIORITY=4\nSYSLOG_IDENTIFIER=helpers\n", read())
+	ui.Error("Error")
+	assert.Contains(read(), "PRIORITY=3\n")
+
+	// failing to set up logging leaves the decision journal alone
+	var buf bytes.Buffer
+	ui.SetJournal(NewJournal(&buf))
+	ui.Log.JournalSocket = filepath.Join(dir, "missing")
+	assert.NotNil(ui.getLogger("journald-test"))
+	assert.NotNil(ui.journal)
+}
+
+func TestUISyslogBackend(t *testing.T) {
+	fmt.Println("+ Testing UI/SyslogBackend...")
+	assert := assert.New(t)
+
+	conn, read := listen(t, "udp", "127.0.0.1:0")
+	defer conn.Close()
+
+	_, err := NewSyslogBackend("tcp", conn.LocalAddr().String(), "test", 0, nil)
+	assert.NotNil(err)
+	_, err = NewSyslogBackend("udp", conn.LocalAddr().String(), "test", 24, nil)
+	assert.NotNil(err)
+
+	s, err := NewSyslogBackend("udp", conn.LocalAddr().String(), "my app", SyslogDaemon, map[string]string{"book": `"Dune" [1]`})
+	require.Nil(t, err)
+	defer s.conn.Close()
+	rec := &logging.Record{Time: time.Now(), Level: logging.WARNING, Args: []interface{}{"Warning"}}
+	assert.Nil(s.Log(logging.WARNING, 0, rec))
+	msg := read()
+	re := regexp.MustCompile(`^<28>1 \d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{6}(Z|[+-]\d\d:\d\d) \S+ myapp \d+ - \[fields@32473 book="\\"Dune\\" \[1\\]"\] Warning$`)
+	assert.Regexp(re, msg)
+
+	// alongside the log file
+	logFilename := "../test/syslog-testing"
+	ui := &UI{Log: LogOptions{Targets: LogToFile | LogToSyslog, SyslogNetwork: "udp", SyslogAddress: conn.LocalAddr().String()}}
+	require.Nil(t, ui.getLogger(logFilename))
+	defer os.Remove(logFilename)
+	ui.Info("Info")
+	// setup message first
+	assert.True(strings.HasPrefix(read(), "<15>1 "))
+	assert.True(strings.HasSuffix(read(), " - Info"))
+	ui.CloseLog()
+	output, err := ioutil.ReadFile(logFilename)
+	assert.Nil(err)
+	assert.Contains(string(output), "Info")
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -14,7 +15,11 @@ var format = logging.MustStringFormatter(
 )
 
 // InitLogger is the main logger, ensure the log file is in the correct XDG directory.
+// Logs can also, or instead, be sent to journald or syslog; see UI.Log.
 func (ui *UI) InitLogger(xdgPath string) (err error) {
+	if !ui.logToFile() {
+		return ui.getLogger(xdgPath)
+	}
 	logPath, err := xdg.Data.Find(xdgPath)
 	if err != nil {
 		logPath, err = xdg.Data.Ensure(xdgPath)
@@ -25,35 +30,72 @@ func (ui *UI) InitLogger(xdgPath string) (err error) {
 	return ui.getLogger(logPath)
 }
 
+// logToFile checks if logs are written to a file.
+func (ui *UI) logToFile() bool {
+	return ui.Log.Targets == 0 || ui.Log.Targets&LogToFile != 0
+}
+
 // getLogger returns a global logger
 func (ui *UI) getLogger(name string) (err error) {
 	ui.logger = logging.MustGetLogger(name)
-	fileName := name
-	ui.logFile, err = os.OpenFile(fileName, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+	backends, conns, err := ui.Log.backends()
+	ui.logConns = conns
 	if err != nil {
-		fmt.Printf("error opening file: %v", err)
+		fmt.Printf("error connecting to log server: %v", err)
+		ui.closeLogOutputs()
 		return
 	}
-	// file log: everything
-	fileLog := logging.NewLogBackend(ui.logFile, "", 0)
-	fileLogFormatter := logging.NewBackendFormatter(fileLog, format)
-	logging.SetBackend(fileLogFormatter)
+	if ui.logToFile() {
+		fileName := name
+		ui.logFile, err = os.OpenFile(fileName, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
+		if err != nil {
+			fmt.Printf("error opening file: %v", err)
+			ui.closeLogOutputs()
+			return
+		}
+		// file log: everything
+		fileLog := logging.NewLogBackend(ui.logFile, "", 0)
+		fileLogFormatter := logging.NewBackendFormatter(fileLog, format)
+		backends = append([]logging.Backend{fileLogFormatter}, backends...)
+	}
+	if len(backends) == 0 {
+		ui.closeLogOutputs()
+		return errors.New("no log target")
+	}
+	logging.SetBackend(backends...)
 	ui.Debug("Logger set up.")
 	return
 }
 
-// CloseLog correctly ends logging.
+// CloseLog correctly ends logging, and closes the decision journal.
 func (ui *UI) CloseLog() {
+	ui.closeLogOutputs()
+	if ui.journal != nil {
+		if err := ui.journal.Close(); err != nil {
+			ui.Error(ui.T("Could not cleanly close decision journal."))
+		}
+		ui.journal = nil
+	}
+}
+
+// closeLogOutputs closes the log file and the sockets to log servers.
+func (ui *UI) closeLogOutputs() {
 	if ui.logFile != nil {
+		ui.logFile.Sync()
 		if err := ui.logFile.Close(); err != nil {
-			ui.Error("Could not cleanly close log file.")
+			ui.Error(ui.T("Could not cleanly close log file."))
 		}
+		ui.logFile = nil
+	}
+	for _, c := range ui.logConns {
+		c.Close()
 	}
+	ui.logConns = nil
 }
 
 // Error message logging.
 func (ui *UI) Error(msg string) {
-	fmt.Println(ui.RedBold("ERROR: " + msg))
+	fmt.Println(ui.Style(ui.theme().Error, ui.glyphs().Error+msg))
 	if ui.logger != nil {
 		ui.logger.Error(msg)
 	}
@@ -62,7 +104,7 @@ func (ui *UI) Error(msg string) {
 // Errorf message logging
 func (ui *UI) Errorf(msg string, args ...interface{}) {
 	msg = fmt.Sprintf(msg, args...)
-	fmt.Println(ui.RedBold("ERROR: " + msg))
+	fmt.Println(ui.Style(ui.theme().Error, ui.glyphs().Error+msg))
 	if ui.logger != nil {
 		ui.logger.Error(msg)
 	}
@@ -70,7 +112,7 @@ func (ui *UI) Errorf(msg string, args ...interface{}) {
 
 // Warning message logging
 func (ui *UI) Warning(msg string) {
-	fmt.Println(ui.Red("WARNING: " + msg))
+	fmt.Println(ui.Style(ui.theme().Warning, ui.glyphs().Warning+msg))
 	if ui.logger != nil {
 		ui.logger.Warning(msg)
 	}
@@ -79,7 +121,7 @@ func (ui *UI) Warning(msg string) {
 // Warningf message logging.
 func (ui *UI) Warningf(msg string, args ...interface{}) {
 	msg = fmt.Sprintf(msg, args...)
-	fmt.Println(ui.Red("WARNING: " + msg))
+	fmt.Println(ui.Style(ui.theme().Warning, ui.glyphs().Warning+msg))
 	if ui.logger != nil {
 		ui.logger.Warning(msg)
 	}
@@ -119,64 +161,89 @@ func (ui *UI) Infof(msg string, args ...interface{}) {
 
 // BlueBold outputs a string in blue bold.
 func (ui *UI) BlueBold(in string) string {
+	if !ui.ColorEnabled() {
+		return in
+	}
 	return chalk.Bold.TextStyle(chalk.Blue.Color(in))
 }
 
 // GreenBold outputs a string in green bold.
 func (ui *UI) GreenBold(in string) string {
+	if !ui.ColorEnabled() {
+		return in
+	}
 	return chalk.Bold.TextStyle(chalk.Green.Color(in))
 }
 
 // CyanBold outputs a string in cyan bold.
 func (ui *UI) CyanBold(in string) string {
+	if !ui.ColorEnabled() {
+		return in
+	}
 	return chalk.Bold.TextStyle(chalk.Cyan.Color(in))
 }
 
 // Green outputs a string in green.
 func (ui *UI) Green(in string) string {
+	if !ui.ColorEnabled() {
+		return in
+	}
 	return chalk.Green.Color(in)
 }
 
 // RedBold outputs a string in red bold.
 func (ui *UI) RedBold(in string) string {
+	if !ui.ColorEnabled() {
+		return in
+	}
 	return chalk.Bold.TextStyle(chalk.Red.Color(in))
 }
 
 // Red outputs a string in red.
 func (ui *UI) Red(in string) string {
+	if !ui.ColorEnabled() {
+		return in
+	}
 	return chalk.Red.Color(in)
 }
 
 // Yellow outputs a string in yellow.
 func (ui *UI) Yellow(in string) string {
+	if !ui.ColorEnabled() {
+		return in
+	}
 	return chalk.Yellow.Color(in)
 }
 
 // YellowBold outputs a string in yellow.
 func (ui *UI) YellowBold(in string) string {
+	if !ui.ColorEnabled() {
+		return in
+	}
 	return chalk.Bold.TextStyle(chalk.Yellow.Color(in))
 }
 
 // Choice message logging
 func (ui *UI) Choice(msg string, args ...interface{}) {
 	msg = fmt.Sprintf(msg, args...)
-	fmt.Print(ui.BlueBold(msg))
+	fmt.Print(ui.Style(ui.theme().Choice, msg))
 }
 
 // Title message logging
 func (ui *UI) Title(msg string, args ...interface{}) {
 	msg = fmt.Sprintf(msg, args...)
-	fmt.Println(ui.GreenBold(msg))
+	fmt.Println(ui.Style(ui.theme().Title, msg))
 }
 
 // SubTitle message logging
 func (ui *UI) SubTitle(msg string, args ...interface{}) {
 	msg = fmt.Sprintf(msg, args...)
-	fmt.Println(ui.Green(" + " + msg))
+	fmt.Println(ui.Style(ui.theme().SubTitle, ui.glyphs().SubTitle+msg))
 }
 
 // SubPart message logging
 func (ui *UI) SubPart(msg string, args ...interface{}) {
 	msg = fmt.Sprintf(msg, args...)
-	fmt.Println(ui.Green("\n ──┤") + ui.GreenBold(msg) + ui.Green("├──"))
+	t, g := ui.theme(), ui.glyphs()
+	fmt.Println("\n" + ui.Style(t.SubPartFrame, g.SubPartLeft) + ui.Style(t.SubPart, msg) + ui.Style(t.SubPartFrame, g.SubPartRight))
 }
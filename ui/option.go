@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sources of the options tagged with Tag.
+const (
+	SourceLocal  = "current"
+	SourceOnline = "online/new"
+)
+
+// Option offered by SelectFrom.
+type Option struct {
+	// Value returned when the option is selected.
+	Value string
+	// Source the value comes from, shown as a label before it, for example
+	// "current" or the name of an online database. It can be empty.
+	Source string
+	// Metadata shown after the value, sorted by key.
+	Metadata map[string]string
+	// Style of the source label. If empty, the theme style for the source is
+	// used.
+	Style Style
+}
+
+// sourceStyle returns the style of the label of an option.
+func (ui UI) sourceStyle(o Option) Style {
+	if o.Style != (Style{}) {
+		return o.Style
+	}
+	t := ui.theme()
+	if s, ok := t.Sources[o.Source]; ok {
+		return s
+	}
+	switch o.Source {
+	case SourceLocal:
+		return t.LocalTag
+	case SourceOnline:
+		return t.OnlineTag
+	}
+	return Style{}
+}
+
+// sourceLabel of an option, such as "[current] ", or nothing if it has no
+// source.
+func (ui UI) sourceLabel(o Option) string {
+	if o.Source == "" {
+		return ""
+	}
+	return ui.Style(ui.sourceStyle(o), "["+o.Source+"] ")
+}
+
+// metadata of an option, as " (key: value, ...)".
+func (ui UI) metadata(o Option) string {
+	if len(o.Metadata) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(o.Metadata))
+	for k := range o.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, len(keys))
+	for i, k := range keys {
+		parts[i] = fmt.Sprintf("%s: %s", k, o.Metadata[k])
+	}
+	return " " + ui.Style(ui.theme().Usage, "("+strings.Join(parts, ", ")+")")
+}
+
+// toOptions converts options that may have been tagged with Tag.
+func (ui UI) toOptions(options []string) []Option {
+	out := make([]Option, len(options))
+	for i, o := range options {
+		value, source := ui.splitTag(o)
+		out[i] = Option{Value: value, Source: source}
+	}
+	return out
+}
+
+// removeDuplicateOptions with the same value and source, and empty values.
+func removeDuplicateOptions(options []Option) []Option {
+	type key struct{ value, source string }
+	seen := make(map[key]bool)
+	var out []Option
+	for _, o := range options {
+		k := key{o.Value, o.Source}
+		if k.value == "" || seen[k] {
+			continue
+		}
+		seen[k] = true
+		out = append(out, o)
+	}
+	return out
+}
+
+// currentOption returns the first option from the local source.
+func currentOption(options []Option) (Option, bool) {
+	for _, o := range options {
+		if o.Source == SourceLocal {
+			return o, true
+		}
+	}
+	return Option{}, false
+}
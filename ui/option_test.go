@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIOptions(t *testing.T) {
+	fmt.Println("+ Testing UI/Option...")
+	assert := assert.New(t)
+	ui := &UI{Color: ColorNever}
+
+	// tagged strings
+	options := ui.toOptions([]string{ui.Tag("local", true), ui.Tag("online", false), " untagged "})
+	assert.Equal([]Option{{Value: "local", Source: SourceLocal}, {Value: "online", Source: SourceOnline}, {Value: "untagged"}}, options)
+
+	// labels are only tags at the start of values
+	options = ui.toOptions([]string{"foo [current] bar", ui.Tag("foo [online/new] bar", true)})
+	assert.Equal([]Option{{Value: "foo [current] bar"}, {Value: "foo [online/new] bar", Source: SourceLocal}}, options)
+	assert.Equal("foo [current] bar", ui.unTag("foo [current] bar"))
+	assert.Equal("foo [current] bar", ui.unTag(ui.Tag("foo [current] bar", false)))
+
+	// labels and metadata
+	assert.Equal("[openlibrary] ", ui.sourceLabel(Option{Source: "openlibrary"}))
+	assert.Equal("", ui.sourceLabel(Option{Value: "no source"}))
+	assert.Equal(" (isbn: 123, year: 2001)", ui.metadata(Option{Metadata: map[string]string{"year": "2001", "isbn": "123"}}))
+	assert.Equal("", ui.metadata(Option{}))
+
+	// styles: option, then theme sources, then theme tags
+	ui.Color = ColorAlways
+	ui.Theme = &Theme{LocalTag: Style{Color: "cyan"}, Sources: map[string]Style{"openlibrary": {Color: "green"}}}
+	assert.Equal(ui.Green("[openlibrary] "), ui.sourceLabel(Option{Source: "openlibrary"}))
+	assert.Equal(ui.Red("[openlibrary] "), ui.sourceLabel(Option{Source: "openlibrary", Style: Style{Color: "red"}}))
+	assert.Equal(ui.Style(Style{Color: "cyan"}, "[current] "), ui.sourceLabel(Option{Source: SourceLocal}))
+	assert.Equal("[other] ", ui.sourceLabel(Option{Source: "other"}))
+
+	// duplicates
+	deduplicated := removeDuplicateOptions([]Option{{Value: "a", Source: "x"}, {Value: "a", Source: "y"}, {Value: "a", Source: "x"}, {Value: "", Source: "x"}})
+	assert.Equal([]Option{{Value: "a", Source: "x"}, {Value: "a", Source: "y"}}, deduplicated)
+	current, ok := currentOption([]Option{{Value: "a", Source: "x"}, {Value: "b", Source: SourceLocal}})
+	assert.True(ok)
+	assert.Equal("b", current.Value)
+	_, ok = currentOption(deduplicated)
+	assert.False(ok)
+}
+
+func TestUISelectFrom(t *testing.T) {
+	fmt.Println("+ Testing UI/SelectFrom()...")
+	assert := assert.New(t)
+	ui := &UI{Color: ColorNever}
+
+	options := []Option{
+		{Value: "Dune", Source: SourceLocal},
+		{Value: "Dune", Source: "goodreads", Metadata: map[string]string{"id": "234225"}},
+		{Value: "Dune", Source: "goodreads"},
+		{Value: "Dune (Dune Chronicles #1)", Source: "openlibrary"},
+	}
+	ui.SetInput(strings.NewReader("3\n"))
+	choice, err := ui.SelectFrom("Title", "", options, false)
+	assert.Nil(err)
+	assert.Equal(options[3], choice)
+
+	ui.SetInput(strings.NewReader("e\nDune: Special Edition\ny\n"))
+	choice, err = ui.SelectFrom("Title", "", options, false)
+	assert.Nil(err)
+	assert.Equal(Option{Value: "Dune: Special Edition"}, choice)
+
+	ui.SetInput(strings.NewReader("b\n"))
+	choice, err = ui.SelectFrom("Title", "", options, false)
+	assert.Nil(err)
+	assert.Equal(Option{}, choice)
+
+	ui.Automation = Automation{Enabled: true, Select: SelectLocal}
+	choice, err = ui.SelectFrom("Title", "", options, false)
+	assert.Nil(err)
+	assert.Equal(options[0], choice)
+}
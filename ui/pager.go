@@ -0,0 +1,284 @@
+package ui
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// EnvPager is the environment variable defining the pager command.
+const EnvPager = "PAGER"
+
+const pagerHelp = "Space/PgDn: next page, Enter/↓: next line, b/PgUp: previous page, ↑: previous line, g/G: start/end, q: quit"
+
+// defaultPager is used if neither Pager.Command nor $PAGER is set.
+// -e Causes less to automatically exit the second time it reaches end-of-file.
+// -F or --quit-if-one-screen  Causes less to automatically exit if the entire file can be displayed on the first screen.
+// -Q Causes totally "quiet" operation: the terminal bell is never rung.
+// -R Outputs colours in raw form.
+// -X or --no-init Disables sending the termcap initialization and deinitialization strings to the terminal. This is sometimes desirable if the deinitialization string does something unnecessary, like clearing the screen.
+var defaultPager = []string{"less", "-e", "-F", "-Q", "-R", "-X", "--buffers=-1"}
+
+// Pager configures how Display shows long texts.
+type Pager struct {
+	// Command and its arguments. If empty, $PAGER is used, then less.
+	// If the command cannot be found, the built-in pager is used.
+	Command []string
+	// Builtin forces the built-in pager.
+	Builtin bool
+	// Always pages text, even if it fits on the screen.
+	Always bool
+	// Output of the pager, stdout by default.
+	Output io.Writer
+}
+
+// command returns the external pager command and its arguments.
+func (p Pager) command() []string {
+	if len(p.Command) != 0 {
+		return p.Command
+	}
+	if env, err := splitShellWords(os.Getenv(EnvPager)); err == nil && len(env) != 0 {
+		return env
+	}
+	return defaultPager
+}
+
+// output of the pager.
+func (p Pager) output() io.Writer {
+	if p.Output == nil {
+		return os.Stdout
+	}
+	return p.Output
+}
+
+// fitsScreen checks if lines can be displayed at once on a screen of width
+// and height, keeping a line for the prompt.
+func fitsScreen(lines []string, width, height int) bool {
+	rows := 0
+	for _, l := range lines {
+		w := runewidth.StringWidth(stripANSI(strings.TrimRight(l, "\r\n")))
+		rows++
+		if w > width {
+			rows += (w - 1) / width
+		}
+	}
+	return rows < height
+}
+
+// Display text, using a pager if it does not fit on the screen.
+func (ui UI) Display(output string) {
+	if err := ui.DisplayReader(strings.NewReader(output)); err != nil {
+		ui.Error(err.Error())
+	}
+}
+
+// DisplayReader displays text as it is read, using a pager if it does not fit
+// on the screen.
+// Text is printed directly if output is not a terminal, or in non-interactive
+// mode.
+func (ui UI) DisplayReader(r io.Reader) error {
+	out := ui.Pager.output()
+	_, auto := ui.automation()
+	if auto || !IsTerminal(out) || ui.input != nil {
+		_, err := io.Copy(out, r)
+		return err
+	}
+
+	// read what fits on the screen
+	width, height := terminalSize()
+	src := bufio.NewReader(r)
+	var lines []string
+	var readErr error
+	for len(lines) < height {
+		var line string
+		line, readErr = src.ReadString('\n')
+		if line != "" {
+			lines = append(lines, line)
+		}
+		if readErr != nil {
+			break
+		}
+	}
+	if readErr != nil && readErr != io.EOF {
+		return readErr
+	}
+	if readErr == io.EOF && !ui.Pager.Always && fitsScreen(lines, width, height) {
+		_, err := io.WriteString(out, strings.Join(lines, ""))
+		return err
+	}
+
+	text := io.MultiReader(strings.NewReader(strings.Join(lines, "")), src)
+	if !ui.Pager.Builtin {
+		command := ui.Pager.command()
+		if _, err := exec.LookPath(command[0]); err == nil {
+			cmd := exec.Command(command[0], command[1:]...)
+			return runCommand(cmd, text, out)
+		}
+	}
+	return ui.builtinPager(text, out, width, height)
+}
+
+func runCommand(cmd *exec.Cmd, input io.Reader, output io.Writer) error {
+	r, stdin := io.Pipe()
+	cmd.Stdin = r
+	cmd.Stdout = output
+	cmd.Stderr = os.Stderr
+	// create a blocking chan, run the pager and unblock once it is finished
+	c := make(chan error)
+	go func() {
+		defer close(c)
+		c <- cmd.Run()
+		// unblock the writer if the pager quit before reading everything
+		r.Close()
+	}()
+	// create a channel to write output to less, because for large amounts of
+	// lines, it's blocking until less displays what was already sent
+	d := make(chan error)
+	go func() {
+		defer close(d)
+		_, err := io.Copy(stdin, input)
+		stdin.CloseWithError(err)
+	}()
+	// wait for the user to quit the pager, and for the writer to give up
+	err := <-c
+	<-d
+	return err
+}
+
+// builtinPager shows text one screen at a time, reading it as needed.
+func (ui UI) builtinPager(text io.Reader, out io.Writer, width, height int) error {
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		// no way to read keys, show everything
+		_, err := io.Copy(out, text)
+		return err
+	}
+	defer restore()
+
+	p := newPagerView(bufio.NewReader(text), width, height-1)
+	for {
+		p.render(out, ui)
+		ui.waitInput()
+		k, err := readKey(ui.reader())
+		if err != nil {
+			fmt.Fprint(out, "\r\033[K")
+			return err
+		}
+		if p.handle(k) {
+			fmt.Fprint(out, "\r\033[K")
+			return p.err
+		}
+	}
+}
+
+// pagerView is the state of the built-in pager.
+type pagerView struct {
+	src *bufio.Reader
+	// rows read so far, wrapped to the width of the screen.
+	rows   []string
+	eof    bool
+	err    error
+	top    int
+	width  int
+	height int
+	// drawn is the number of rows drawn on screen.
+	drawn int
+}
+
+func newPagerView(src *bufio.Reader, width, height int) *pagerView {
+	if height < 1 {
+		height = 1
+	}
+	return &pagerView{src: src, width: width, height: height}
+}
+
+// fill reads text until n rows are available, or the end of the text.
+func (p *pagerView) fill(n int) {
+	for len(p.rows) < n && !p.eof {
+		line, err := p.src.ReadString('\n')
+		if err != nil {
+			p.eof = true
+			if err != io.EOF {
+				p.err = err
+			}
+			if line == "" {
+				break
+			}
+		}
+		line = strings.Replace(strings.TrimRight(line, "\r\n"), "\t", "    ", -1)
+		p.rows = append(p.rows, wrapStyled(line, p.width)...)
+	}
+}
+
+// scroll by delta rows, staying within the text.
+func (p *pagerView) scroll(delta int) {
+	p.top += delta
+	p.fill(p.top + p.height)
+	if p.top > len(p.rows)-p.height {
+		p.top = len(p.rows) - p.height
+	}
+	if p.top < 0 {
+		p.top = 0
+	}
+}
+
+// atEnd checks if the last row is on screen.
+func (p *pagerView) atEnd() bool {
+	p.fill(p.top + p.height + 1)
+	return p.eof && p.top+p.height >= len(p.rows)
+}
+
+// handle a key press, returning true when the pager should quit.
+func (p *pagerView) handle(k keyPress) bool {
+	switch {
+	case k.key == keyEscape, k.key == keyInterrupt, k.key == keyEOF, k.key == keyRune && (k.char == 'q' || k.char == 'Q'):
+		return true
+	case k.key == keyPageDown, k.key == keyRune && (k.char == ' ' || k.char == 'f'):
+		if p.atEnd() {
+			return true
+		}
+		p.scroll(p.height)
+	case k.key == keyDown, k.key == keyEnter, k.key == keyRune && k.char == 'j':
+		p.scroll(1)
+	case k.key == keyPageUp, k.key == keyRune && k.char == 'b':
+		p.scroll(-p.height)
+	case k.key == keyUp, k.key == keyRune && k.char == 'k':
+		p.scroll(-1)
+	case k.key == keyHome, k.key == keyRune && k.char == 'g':
+		p.scroll(-p.top)
+	case k.key == keyEnd, k.key == keyRune && k.char == 'G':
+		for !p.eof {
+			p.fill(len(p.rows) + p.height)
+		}
+		p.scroll(len(p.rows))
+	}
+	return false
+}
+
+// render the current screen and the prompt.
+func (p *pagerView) render(w io.Writer, ui UI) {
+	p.fill(p.top + p.height)
+	fmt.Fprint(w, "\r\033[K")
+	if p.drawn != 0 {
+		// redraw the whole screen
+		fmt.Fprintf(w, "\033[%dA\033[J", p.drawn)
+	}
+	end := p.top + p.height
+	if end > len(p.rows) {
+		end = len(p.rows)
+	}
+	for _, row := range p.rows[p.top:end] {
+		fmt.Fprint(w, row+"\r\n")
+	}
+	p.drawn = end - p.top
+	status := fmt.Sprintf("--More-- (%d-%d) %s", p.top+1, end, ui.T(pagerHelp))
+	if p.atEnd() {
+		status = fmt.Sprintf("(END) %s", ui.T(pagerHelp))
+	}
+	fmt.Fprint(w, ui.Style(ui.theme().Usage, runewidth.Truncate(status, p.width, "…")))
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIPager(t *testing.T) {
+	fmt.Println("+ Testing UI/Pager...")
+	assert := assert.New(t)
+
+	restore := setenv(map[string]string{EnvPager: ""})
+	assert.Equal(defaultPager, Pager{}.command())
+	restore()
+	restore = setenv(map[string]string{EnvPager: "most -s"})
+	assert.Equal([]string{"most", "-s"}, Pager{}.command())
+	assert.Equal([]string{"more"}, Pager{Command: []string{"more"}}.command())
+	restore()
+
+	assert.True(fitsScreen([]string{"one\n", "two\n"}, 80, 24))
+	assert.True(fitsScreen(make([]string, 23), 80, 24))
+	assert.False(fitsScreen(make([]string, 24), 80, 24))
+	assert.False(fitsScreen([]string{strings.Repeat("x", 81)}, 80, 2))
+	assert.True(fitsScreen([]string{strings.Repeat("x", 80)}, 80, 2))
+
+	// output is not a terminal: text is copied directly
+	out := &bytes.Buffer{}
+	ui := &UI{Pager: Pager{Output: out}}
+	assert.Nil(ui.DisplayReader(strings.NewReader("some\ntext\n")))
+	assert.Equal("some\ntext\n", out.String())
+}
+
+func TestUIPagerView(t *testing.T) {
+	fmt.Println("+ Testing UI/Pager built-in...")
+	assert := assert.New(t)
+
+	var text []string
+	for i := 1; i <= 10; i++ {
+		text = append(text, fmt.Sprintf("line %d", i))
+	}
+	p := newPagerView(bufio.NewReader(strings.NewReader(strings.Join(text, "\n"))), 80, 4)
+	ui := UI{Color: ColorNever}
+	out := &bytes.Buffer{}
+	p.render(out, ui)
+	assert.Equal(4, p.drawn)
+	assert.Contains(out.String(), "line 4\r\n")
+	assert.NotContains(out.String(), "line 5")
+	assert.Contains(out.String(), "--More-- (1-4)")
+	assert.True(len(p.rows) < 10, "text should be read as needed")
+
+	assert.False(p.handle(keyPress{key: keyRune, char: ' '}))
+	assert.Equal(4, p.top)
+	assert.False(p.handle(keyPress{key: keyDown}))
+	assert.Equal(5, p.top)
+	assert.False(p.handle(keyPress{key: keyRune, char: 'G'}))
+	assert.Equal(6, p.top)
+	out.Reset()
+	p.render(out, ui)
+	assert.Contains(out.String(), "line 10\r\n")
+	assert.Contains(out.String(), "(END)")
+	assert.False(p.handle(keyPress{key: keyPageUp}))
+	assert.Equal(2, p.top)
+	assert.False(p.handle(keyPress{key: keyRune, char: 'g'}))
+	assert.Equal(0, p.top)
+	assert.True(p.handle(keyPress{key: keyRune, char: 'q'}))
+
+	// quit after reaching the end
+	assert.False(p.handle(keyPress{key: keyEnd}))
+	assert.True(p.handle(keyPress{key: keyPageDown}))
+
+	// long lines are wrapped
+	p = newPagerView(bufio.NewReader(strings.NewReader(strings.Repeat("x", 25))), 10, 4)
+	p.fill(4)
+	assert.Equal([]string{strings.Repeat("x", 10), strings.Repeat("x", 10), strings.Repeat("x", 5)}, p.rows)
+}
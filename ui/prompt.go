@@ -0,0 +1,217 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Validator checks user input, returning an error explaining why it is
+// invalid.
+type Validator func(string) error
+
+// ValidateAll combines validators, returning the first error.
+func ValidateAll(validators ...Validator) Validator {
+	return func(input string) error {
+		for _, v := range validators {
+			if v == nil {
+				continue
+			}
+			if err := v(input); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// ValidateNotEmpty rejects empty input.
+func ValidateNotEmpty(input string) error {
+	if input == "" {
+		return errors.New(emptyValue)
+	}
+	return nil
+}
+
+// ValidateInt accepts integers between min and max, included.
+func ValidateInt(min, max int) Validator {
+	return func(input string) error {
+		i, err := strconv.Atoi(input)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", input)
+		}
+		if i < min || i > max {
+			return fmt.Errorf("%d is not between %d and %d", i, min, max)
+		}
+		return nil
+	}
+}
+
+// ValidateFloat accepts numbers between min and max, included.
+func ValidateFloat(min, max float64) Validator {
+	return func(input string) error {
+		f, err := strconv.ParseFloat(input, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", input)
+		}
+		if f < min || f > max {
+			return fmt.Errorf("%g is not between %g and %g", f, min, max)
+		}
+		return nil
+	}
+}
+
+// ValidateDate accepts dates in one of the given layouts.
+func ValidateDate(layouts ...string) Validator {
+	return func(input string) error {
+		_, err := parseDate(input, layouts)
+		return err
+	}
+}
+
+// ValidatePathExists accepts paths of existing files or directories.
+func ValidatePathExists(input string) error {
+	if _, err := os.Stat(input); err != nil {
+		return fmt.Errorf("%q does not exist", input)
+	}
+	return nil
+}
+
+// ValidateRegex accepts input matching a regular expression.
+func ValidateRegex(re *regexp.Regexp) Validator {
+	return func(input string) error {
+		if !re.MatchString(input) {
+			return fmt.Errorf("%q does not match %s", input, re.String())
+		}
+		return nil
+	}
+}
+
+// ValidateISBN accepts valid ISBN-10 or ISBN-13, with or without hyphens.
+func ValidateISBN(input string) error {
+	isbn := strings.Replace(strings.Replace(strings.ToUpper(input), "-", "", -1), " ", "", -1)
+	invalid := fmt.Errorf("%q is not a valid ISBN", input)
+	sum := 0
+	switch len(isbn) {
+	case 10:
+		for i, c := range isbn {
+			var digit int
+			switch {
+			case c >= '0' && c <= '9':
+				digit = int(c - '0')
+			case c == 'X' && i == 9:
+				digit = 10
+			default:
+				return invalid
+			}
+			sum += (10 - i) * digit
+		}
+		if sum%11 != 0 {
+			return invalid
+		}
+	case 13:
+		for i, c := range isbn {
+			if c < '0' || c > '9' {
+				return invalid
+			}
+			weight := 1
+			if i%2 == 1 {
+				weight = 3
+			}
+			sum += weight * int(c-'0')
+		}
+		if sum%10 != 0 {
+			return invalid
+		}
+	default:
+		return invalid
+	}
+	return nil
+}
+
+// Prompt asks a question until the answer is valid, showing why it is not.
+// It gives up after too many invalid answers.
+func Prompt(ui UserInterface, question string, validate Validator) (string, error) {
+	errs := 0
+	for {
+		ui.Choice("%s: ", question)
+		input, err := ui.GetInput()
+		if err != nil {
+			return "", err
+		}
+		if validate == nil {
+			return input, nil
+		}
+		err = validate(input)
+		if err == nil {
+			return input, nil
+		}
+		ui.Warning(err.Error())
+		errs++
+		if errs > maxErrors {
+			ui.Warning(translate(ui, tooManyErrors))
+			return "", errors.New(invalidChoice)
+		}
+	}
+}
+
+// PromptInt asks for an integer between min and max, included.
+func PromptInt(ui UserInterface, question string, min, max int, validators ...Validator) (int, error) {
+	input, err := Prompt(ui, question, ValidateAll(append([]Validator{ValidateInt(min, max)}, validators...)...))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(input)
+}
+
+// PromptFloat asks for a number between min and max, included.
+func PromptFloat(ui UserInterface, question string, min, max float64, validators ...Validator) (float64, error) {
+	input, err := Prompt(ui, question, ValidateAll(append([]Validator{ValidateFloat(min, max)}, validators...)...))
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(input, 64)
+}
+
+// PromptDate asks for a date in one of the given layouts, or YYYY-MM-DD if
+// none is given.
+func PromptDate(ui UserInterface, question string, layouts ...string) (time.Time, error) {
+	if len(layouts) == 0 {
+		layouts = []string{"2006-01-02"}
+	}
+	input, err := Prompt(ui, fmt.Sprintf("%s (%s)", question, strings.Join(layouts, ", ")), ValidateDate(layouts...))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return parseDate(input, layouts)
+}
+
+// PromptPath asks for the path of an existing file or directory, and returns
+// its absolute path.
+func PromptPath(ui UserInterface, question string, validators ...Validator) (string, error) {
+	input, err := Prompt(ui, question, ValidateAll(append([]Validator{ValidatePathExists}, validators...)...))
+	if err != nil {
+		return "", err
+	}
+	return filepath.Abs(input)
+}
+
+// PromptRegex asks for input matching a regular expression.
+func PromptRegex(ui UserInterface, question string, re *regexp.Regexp, validators ...Validator) (string, error) {
+	return Prompt(ui, question, ValidateAll(append([]Validator{ValidateRegex(re)}, validators...)...))
+}
+
+// parseDate in the first matching layout.
+func parseDate(input string, layouts []string) (time.Time, error) {
+	for _, layout := range layouts {
+		if t, err := time.Parse(layout, input); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%q is not a valid date (%s)", input, strings.Join(layouts, ", "))
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIValidators(t *testing.T) {
+	fmt.Println("+ Testing UI/Validators...")
+	assert := assert.New(t)
+
+	for _, isbn := range []string{"2-07-036002-4", "0-8044-2957-X", "978-2-07-036002-4", "9780306406157"} {
+		assert.Nil(ValidateISBN(isbn), isbn)
+	}
+	for _, isbn := range []string{"2-07-036002-5", "978-2-07-036002-5", "97802070360024", "X-07-036002-4", ""} {
+		assert.NotNil(ValidateISBN(isbn), isbn)
+	}
+	assert.Nil(ValidateAll(ValidateNotEmpty, nil, ValidateInt(0, 3))("3"))
+	assert.NotNil(ValidateAll(ValidateNotEmpty, ValidateInt(0, 3))(""))
+	assert.NotNil(ValidateFloat(0, 1)("1.5"))
+	assert.Nil(ValidateDate("02/01/2006", "2006")("1830"))
+	assert.NotNil(ValidatePathExists("/does/not/exist"))
+}
+
+func TestUIPrompts(t *testing.T) {
+	fmt.Println("+ Testing UI/Prompt...()...")
+	assert := assert.New(t)
+	ui := &UI{}
+
+	ui.SetInput(strings.NewReader("abc\n3000\n1862\n"))
+	year, err := PromptInt(ui, "Year", 0, 2017)
+	assert.Nil(err)
+	assert.Equal(1862, year)
+
+	ui.SetInput(strings.NewReader("-1\n4.5\n"))
+	rating, err := PromptFloat(ui, "Rating", 0, 5)
+	assert.Nil(err)
+	assert.Equal(4.5, rating)
+
+	ui.SetInput(strings.NewReader("1862-13-01\n03/04/1862\n"))
+	date, err := PromptDate(ui, "Published", "2006-01-02", "02/01/2006")
+	assert.Nil(err)
+	assert.Equal(time.Date(1862, time.April, 3, 0, 0, 0, 0, time.UTC), date)
+
+	wd, err := os.Getwd()
+	assert.Nil(err)
+	ui.SetInput(strings.NewReader("/does/not/exist\n.\n"))
+	path, err := PromptPath(ui, "Directory")
+	assert.Nil(err)
+	assert.Equal(wd, path)
+
+	ui.SetInput(strings.NewReader("lowercase\nUPPERCASE\n"))
+	text, err := PromptRegex(ui, "Code", regexp.MustCompile(`^[A-Z]+$`))
+	assert.Nil(err)
+	assert.Equal("UPPERCASE", text)
+
+	// too many errors
+	ui.SetInput(strings.NewReader(strings.Repeat("x\n", maxErrors+1) + "1\n"))
+	_, err = PromptInt(ui, "Year", 0, 2017)
+	assert.NotNil(err)
+}
+
+// choiceRecorder remembers the prompts shown with Choice.
+type choiceRecorder struct {
+	*UI
+	prompts []string
+}
+
+func (c *choiceRecorder) Choice(msg string, args ...interface{}) {
+	c.prompts = append(c.prompts, fmt.Sprintf(msg, args...))
+}
+
+func TestUIPromptFormat(t *testing.T) {
+	fmt.Println("+ Testing UI/Prompt() with % in questions...")
+	assert := assert.New(t)
+	c := &choiceRecorder{UI: &UI{}}
+	c.SetInput(strings.NewReader("10\n"))
+	discount, err := PromptInt(c, "Discount (%)", 0, 100)
+	assert.Nil(err)
+	assert.Equal(10, discount)
+	assert.Equal([]string{"Discount (%): "}, c.prompts)
+}
@@ -0,0 +1,394 @@
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+	"launchpad.net/go-xdg"
+)
+
+const maxHistory = 1000
+
+// ErrInterrupted is returned when the user hits Ctrl-C while editing a line.
+var ErrInterrupted = errors.New("interrupted")
+
+// Completer returns the possible completions of a line.
+type Completer func(line string) []string
+
+// WordCompleter completes a line with the words starting with it, regardless
+// of case.
+func WordCompleter(words []string) Completer {
+	return func(line string) []string {
+		var candidates []string
+		for _, w := range words {
+			if strings.HasPrefix(strings.ToLower(w), strings.ToLower(line)) {
+				candidates = append(candidates, w)
+			}
+		}
+		return candidates
+	}
+}
+
+// PathCompleter completes a line with existing paths starting with it.
+// Directories end with a separator.
+func PathCompleter(line string) []string {
+	matches, err := filepath.Glob(line + "*")
+	if err != nil {
+		return nil
+	}
+	for i, m := range matches {
+		if isDirectory(m) {
+			matches[i] = m + string(filepath.Separator)
+		}
+	}
+	return matches
+}
+
+// isDirectory checks if a directory exists.
+func isDirectory(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// History of user inputs, saved to a file.
+type History struct {
+	path    string
+	entries []string
+	// saved is the number of entries in the file.
+	saved int
+}
+
+// LoadHistory from a file in the correct XDG data directory, creating it if
+// necessary.
+func LoadHistory(xdgPath string) (*History, error) {
+	path, err := xdg.Data.Find(xdgPath)
+	if err != nil {
+		path, err = xdg.Data.Ensure(xdgPath)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return loadHistoryFile(path)
+}
+
+// loadHistoryFile from an absolute path.
+func loadHistoryFile(path string) (*History, error) {
+	h := &History{path: path}
+	content, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	for _, line := range strings.Split(string(content), "\n") {
+		if line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	h.saved = len(h.entries)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[len(h.entries)-maxHistory:]
+	}
+	return h, nil
+}
+
+// Entries of the history, oldest first.
+func (h *History) Entries() []string {
+	return h.entries
+}
+
+// Add a line to the history, and save it.
+// Empty lines and repetitions of the last entry are ignored.
+// The file is rewritten with the last entries when it gets too long.
+func (h *History) Add(line string) error {
+	if line == "" || strings.Contains(line, "\n") || len(h.entries) != 0 && h.entries[len(h.entries)-1] == line {
+		return nil
+	}
+	h.entries = append(h.entries, line)
+	if len(h.entries) > maxHistory {
+		h.entries = h.entries[1:]
+	}
+	if h.path == "" {
+		return nil
+	}
+	if h.saved >= maxHistory {
+		return h.rewrite()
+	}
+	f, err := os.OpenFile(h.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	h.saved++
+	return f.Close()
+}
+
+// rewrite the file with the entries, replacing it only once it is written.
+func (h *History) rewrite() error {
+	tmp := h.path + ".tmp"
+	if err := ioutil.WriteFile(tmp, []byte(strings.Join(h.entries, "\n")+"\n"), 0600); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	h.saved = len(h.entries)
+	return nil
+}
+
+// lineEditor is the state of a line being edited.
+type lineEditor struct {
+	buffer  []rune
+	pos     int
+	history []string
+	// histIndex is the history entry being displayed, len(history) for the
+	// line being typed, saved in current.
+	histIndex int
+	current   []rune
+	completer Completer
+	// candidates of the completion in progress, cycled through with Tab.
+	candidates []string
+	candidate  int
+	// width of what was displayed, and cursor position, in cells.
+	drawnWidth  int
+	drawnCursor int
+}
+
+func newLineEditor(history []string, completer Completer) *lineEditor {
+	return &lineEditor{history: history, histIndex: len(history), completer: completer}
+}
+
+// set the whole line.
+func (e *lineEditor) set(line []rune) {
+	e.buffer = append([]rune{}, line...)
+	e.pos = len(e.buffer)
+}
+
+// handle a key press, returning true when the line is finished.
+func (e *lineEditor) handle(k keyPress) (bool, error) {
+	if k.key != keyTab {
+		e.candidates = nil
+	}
+	switch k.key {
+	case keyEnter:
+		return true, nil
+	case keyInterrupt:
+		return true, ErrInterrupted
+	case keyEOF:
+		if len(e.buffer) == 0 {
+			return true, io.EOF
+		}
+		e.delete(e.pos, e.pos+1)
+	case keyRune:
+		e.buffer = append(e.buffer[:e.pos], append([]rune{k.char}, e.buffer[e.pos:]...)...)
+		e.pos++
+	case keyBackspace:
+		e.delete(e.pos-1, e.pos)
+	case keyDelete:
+		e.delete(e.pos, e.pos+1)
+	case keyLeft:
+		if e.pos > 0 {
+			e.pos--
+		}
+	case keyRight:
+		if e.pos < len(e.buffer) {
+			e.pos++
+		}
+	case keyHome:
+		e.pos = 0
+	case keyEnd:
+		e.pos = len(e.buffer)
+	case keyKillToEnd:
+		e.delete(e.pos, len(e.buffer))
+	case keyKillToStart:
+		e.delete(0, e.pos)
+	case keyKillWord:
+		start := e.pos
+		for start > 0 && unicode.IsSpace(e.buffer[start-1]) {
+			start--
+		}
+		for start > 0 && !unicode.IsSpace(e.buffer[start-1]) {
+			start--
+		}
+		e.delete(start, e.pos)
+	case keyUp:
+		if e.histIndex > 0 {
+			if e.histIndex == len(e.history) {
+				e.current = append([]rune{}, e.buffer...)
+			}
+			e.histIndex--
+			e.set([]rune(e.history[e.histIndex]))
+		}
+	case keyDown:
+		if e.histIndex < len(e.history) {
+			e.histIndex++
+			if e.histIndex == len(e.history) {
+				e.set(e.current)
+			} else {
+				e.set([]rune(e.history[e.histIndex]))
+			}
+		}
+	case keyTab:
+		e.complete()
+	}
+	return false, nil
+}
+
+// delete runes between start and end.
+func (e *lineEditor) delete(start, end int) {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(e.buffer) {
+		end = len(e.buffer)
+	}
+	if start >= end {
+		return
+	}
+	e.buffer = append(e.buffer[:start], e.buffer[end:]...)
+	if e.pos > end {
+		e.pos -= end - start
+	} else if e.pos > start {
+		e.pos = start
+	}
+}
+
+// complete the line: the first Tab completes the longest common prefix of all
+// candidates, the next ones cycle through them.
+func (e *lineEditor) complete() {
+	if e.completer == nil {
+		return
+	}
+	if e.candidates != nil {
+		e.candidate = (e.candidate + 1) % len(e.candidates)
+		e.set([]rune(e.candidates[e.candidate]))
+		return
+	}
+	candidates := e.completer(string(e.buffer))
+	switch len(candidates) {
+	case 0:
+		return
+	case 1:
+		e.set([]rune(candidates[0]))
+		return
+	}
+	prefix := []rune(candidates[0])
+	for _, c := range candidates[1:] {
+		r := []rune(c)
+		i := 0
+		for i < len(prefix) && i < len(r) && prefix[i] == r[i] {
+			i++
+		}
+		prefix = prefix[:i]
+	}
+	if len(prefix) > len(e.buffer) {
+		e.set(prefix)
+	}
+	e.candidates = candidates
+	e.candidate = -1
+}
+
+// render the line, replacing what was previously drawn after the prompt.
+func (e *lineEditor) render(w io.Writer) {
+	if e.drawnCursor > 0 {
+		fmt.Fprintf(w, "\033[%dD", e.drawnCursor)
+	}
+	line := string(e.buffer)
+	fmt.Fprint(w, "\033[K"+line)
+	e.drawnWidth = runewidth.StringWidth(line)
+	e.drawnCursor = runewidth.StringWidth(string(e.buffer[:e.pos]))
+	if back := e.drawnWidth - e.drawnCursor; back > 0 {
+		fmt.Fprintf(w, "\033[%dD", back)
+	}
+}
+
+// readLine from a terminal in raw mode, with line editing.
+func readLine(r *bufio.Reader, w io.Writer, history []string, completer Completer) (string, error) {
+	e := newLineEditor(history, completer)
+	for {
+		k, err := readKey(r)
+		if err != nil {
+			return string(e.buffer), err
+		}
+		done, err := e.handle(k)
+		if done {
+			fmt.Fprint(w, "\r\n")
+			return string(e.buffer), err
+		}
+		e.render(w)
+	}
+}
+
+// EnableLineEditing lets the user edit input lines like a shell does, with
+// history saved in the correct XDG data directory.
+// Line editing is only used if the terminal is interactive.
+func (ui *UI) EnableLineEditing(historyXdgPath string) error {
+	history, err := LoadHistory(historyXdgPath)
+	if err != nil {
+		return err
+	}
+	ui.history = history
+	return nil
+}
+
+// GetInputWithCompletion from user, completing it with Tab if line editing
+// is enabled. The answer is added to the history.
+func (ui UI) GetInputWithCompletion(completer Completer) (string, error) {
+	return ui.getLine(completer, completer != nil)
+}
+
+// GetInputWithHistory from user, adding the answer to the history if line
+// editing is enabled. GetInput does not, so that short answers to menus
+// do not clutter it.
+func (ui UI) GetInputWithHistory() (string, error) {
+	return ui.getLine(nil, true)
+}
+
+// getLine from user, with line editing if it is enabled, and adding it to
+// the history if remember is set.
+func (ui UI) getLine(completer Completer, remember bool) (string, error) {
+	if ui.waitInput() {
+		// typed without line editing while waiting
+		return ui.readInputLine()
+	}
+	if _, auto := ui.automation(); auto {
+		ui.logDecision("cannot get input")
+		return "", ErrNonInteractive
+	}
+	if ui.history == nil || ui.input != nil || !IsInteractive() {
+		return ui.readInputLine()
+	}
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		return ui.readInputLine()
+	}
+	line, err := readLine(ui.reader(), os.Stdout, ui.history.Entries(), completer)
+	restore()
+	if err != nil {
+		return "", err
+	}
+	line = strings.TrimSpace(line)
+	if remember {
+		ui.remember(line)
+	}
+	return line, nil
+}
+
+// remember a line in the history, only logging errors since the line was
+// read anyway.
+func (ui UI) remember(line string) {
+	if err := ui.history.Add(line); err != nil {
+		ui.Warningf("could not save history: %s", err.Error())
+	}
+}
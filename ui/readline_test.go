@@ -0,0 +1,116 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUILineEditor(t *testing.T) {
+	fmt.Println("+ Testing UI/readLine()...")
+	assert := assert.New(t)
+
+	read := func(input string, history []string, completer Completer) (string, error) {
+		return readLine(bufio.NewReader(strings.NewReader(input)), ioutil.Discard, history, completer)
+	}
+
+	// typing, moving, deleting
+	line, err := read("helo\x1b[D\x1b[Dl\x1b[F!\x01\x1b[3~H\r", nil, nil)
+	assert.Nil(err)
+	assert.Equal("Hello!", line)
+	line, err = read("Victor Hugo\x17Marie Hugo\x01\x0b\r", nil, nil)
+	assert.Nil(err)
+	assert.Equal("", line)
+	line, err = read("Victor Hugo\x1b[D\x1b[D\x15\r", nil, nil)
+	assert.Nil(err)
+	assert.Equal("go", line)
+
+	// history
+	history := []string{"first", "second"}
+	line, err = read("new\x1b[A\x1b[A\x1b[A\r", history, nil)
+	assert.Nil(err)
+	assert.Equal("first", line)
+	line, err = read("new\x1b[A\x1b[B\r", history, nil)
+	assert.Nil(err)
+	assert.Equal("new", line)
+
+	// completion
+	completer := WordCompleter([]string{"Victor Hugo", "Victor Segalen", "Stendhal"})
+	line, err = read("st\t\r", nil, completer)
+	assert.Nil(err)
+	assert.Equal("Stendhal", line)
+	line, err = read("vic\t\r", nil, completer)
+	assert.Nil(err)
+	assert.Equal("Victor ", line)
+	line, err = read("vic\t\t\t\r", nil, completer)
+	assert.Nil(err)
+	assert.Equal("Victor Segalen", line)
+
+	// interrupting, end of input
+	_, err = read("abc\x03", nil, nil)
+	assert.Equal(ErrInterrupted, err)
+	_, err = read("\x04", nil, nil)
+	assert.Equal(io.EOF, err)
+
+	// rendering with wide characters
+	e := newLineEditor(nil, nil)
+	for _, c := range "三国" {
+		e.handle(keyPress{key: keyRune, char: c})
+	}
+	e.handle(keyPress{key: keyLeft})
+	output := &bytes.Buffer{}
+	e.render(output)
+	assert.Equal("\x1b[K三国\x1b[2D", output.String())
+}
+
+func TestUIHistory(t *testing.T) {
+	fmt.Println("+ Testing UI/History...")
+	assert := assert.New(t)
+
+	dir, err := ioutil.TempDir("", "history")
+	require.Nil(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "history")
+
+	h, err := loadHistoryFile(path)
+	require.Nil(t, err)
+	assert.Nil(h.Add("one"))
+	assert.Nil(h.Add("one"))
+	assert.Nil(h.Add(""))
+	assert.Nil(h.Add("two"))
+	assert.Equal([]string{"one", "two"}, h.Entries())
+
+	h, err = loadHistoryFile(path)
+	require.Nil(t, err)
+	assert.Equal([]string{"one", "two"}, h.Entries())
+
+	// the file does not grow beyond the history
+	for n := 0; n < maxHistory+10; n++ {
+		assert.Nil(h.Add(fmt.Sprintf("line %d", n)))
+	}
+	content, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.Equal(maxHistory, strings.Count(string(content), "\n"))
+	h, err = loadHistoryFile(path)
+	require.Nil(t, err)
+	assert.Equal(maxHistory, len(h.Entries()))
+	assert.Equal(fmt.Sprintf("line %d", maxHistory+9), h.Entries()[maxHistory-1])
+
+	// failing to save the history is not an error for the user input
+	ui := UI{history: &History{path: filepath.Join(dir, "missing", "history")}}
+	ui.remember("three")
+	assert.Equal([]string{"three"}, ui.history.Entries())
+
+	// path completion
+	require.Nil(t, os.Mkdir(filepath.Join(dir, "subdir"), 0700))
+	assert.Equal([]string{path, filepath.Join(dir, "subdir") + string(filepath.Separator)}, PathCompleter(filepath.Join(dir, "")+string(filepath.Separator)))
+}
@@ -0,0 +1,178 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+)
+
+const editRecordHelp = "Lines starting with # are ignored.\nSave the file unchanged to abort."
+
+// RecordFormat is a serialisation format for EditStruct.
+type RecordFormat int
+
+// Record formats.
+const (
+	RecordYAML RecordFormat = iota
+	RecordTOML
+	RecordJSON
+)
+
+var recordFormats = map[string]RecordFormat{
+	"yaml": RecordYAML,
+	"yml":  RecordYAML,
+	"toml": RecordTOML,
+	"json": RecordJSON,
+}
+
+// ParseRecordFormat returns the format for a name (yaml, yml, toml or json).
+func ParseRecordFormat(name string) (RecordFormat, error) {
+	format, ok := recordFormats[strings.ToLower(name)]
+	if !ok {
+		return RecordYAML, fmt.Errorf("unknown record format %q", name)
+	}
+	return format, nil
+}
+
+// extension of files in this format.
+func (f RecordFormat) extension() string {
+	switch f {
+	case RecordTOML:
+		return "toml"
+	case RecordJSON:
+		return "json"
+	}
+	return "yaml"
+}
+
+// marshal v in this format.
+func (f RecordFormat) marshal(v interface{}) ([]byte, error) {
+	switch f {
+	case RecordTOML:
+		var buf bytes.Buffer
+		err := toml.NewEncoder(&buf).Encode(v)
+		return buf.Bytes(), err
+	case RecordJSON:
+		return json.MarshalIndent(v, "", "  ")
+	}
+	return yaml.Marshal(v)
+}
+
+// unmarshal data in this format into v.
+func (f RecordFormat) unmarshal(data []byte, v interface{}) error {
+	switch f {
+	case RecordTOML:
+		_, err := toml.Decode(string(data), v)
+		return err
+	case RecordJSON:
+		return json.Unmarshal(data, v)
+	}
+	return yaml.UnmarshalStrict(data, v)
+}
+
+var yamlErrorLine = regexp.MustCompile(`line (\d+):`)
+
+// errorLine returns the line where parsing data failed, starting at 1, or 0
+// if it is unknown.
+func errorLine(data []byte, err error) int {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	var tomlErr toml.ParseError
+	switch {
+	case errors.As(err, &syntaxErr):
+		return 1 + bytes.Count(data[:clampOffset(syntaxErr.Offset, data)], []byte("\n"))
+	case errors.As(err, &typeErr):
+		return 1 + bytes.Count(data[:clampOffset(typeErr.Offset, data)], []byte("\n"))
+	case errors.As(err, &tomlErr):
+		return tomlErr.Position.Line
+	}
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return line
+	}
+	return 0
+}
+
+// clampOffset to the length of data.
+func clampOffset(offset int64, data []byte) int64 {
+	if offset > int64(len(data)) {
+		return int64(len(data))
+	}
+	return offset
+}
+
+// annotate text with an error, as a comment after the line where it occurred,
+// or at the top if the line is unknown.
+func annotate(text string, line int, err error) string {
+	var comments []string
+	for _, l := range strings.Split(err.Error(), "\n") {
+		comments = append(comments, defaultComment+" error: "+l)
+	}
+	lines := strings.Split(text, "\n")
+	if line <= 0 || line > len(lines) {
+		return strings.Join(append(comments, lines...), "\n")
+	}
+	return strings.Join(append(lines[:line], append(comments, lines[line:]...)...), "\n")
+}
+
+// EditStruct edits a struct or a map, pointed to by v, with an external
+// editor, serialised in the given format.
+// Once saved, the text is parsed and checked with validate, if not nil, which
+// receives a pointer to the parsed value. If
+// either fails, the editor is opened again with the errors as comments.
+// v is only modified if everything succeeds. ErrEditUnchanged is returned if
+// the text was saved unchanged the first time; after an error, the error is
+// returned instead.
+func (ui *UI) EditStruct(v interface{}, format RecordFormat, validate func(interface{}) error) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.IsNil() {
+		return errors.New("EditStruct needs a non-nil pointer")
+	}
+	if _, auto := ui.automation(); auto {
+		ui.logDecision("edition skipped, kept current values")
+		return nil
+	}
+	data, err := format.marshal(v)
+	if err != nil {
+		return err
+	}
+	text := string(data)
+	options := EditOptions{Extension: format.extension(), Help: ui.T(editRecordHelp), Comment: defaultComment}
+
+	var lastErr error
+	for errs := 0; errs <= maxErrors; errs++ {
+		edited, err := ui.EditWithOptions(text, options)
+		if err == ErrEditUnchanged && lastErr != nil {
+			return lastErr
+		}
+		if err != nil {
+			return err
+		}
+
+		parsed := reflect.New(target.Elem().Type())
+		line := 0
+		err = format.unmarshal([]byte(edited), parsed.Interface())
+		if err != nil {
+			line = errorLine([]byte(edited), err)
+		} else if validate != nil {
+			err = validate(parsed.Interface())
+		}
+		if err == nil {
+			target.Elem().Set(parsed.Elem())
+			return nil
+		}
+		ui.Warning(err.Error())
+		lastErr = err
+		text = annotate(edited, line, err)
+	}
+	ui.Warning(ui.T(tooManyErrors))
+	return lastErr
+}
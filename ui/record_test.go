@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testBook struct {
+	Title  string   `yaml:"title" toml:"title" json:"title"`
+	Year   int      `yaml:"year" toml:"year" json:"year"`
+	Series []string `yaml:"series" toml:"series" json:"series"`
+}
+
+// fakeEditor replaces the edited file with each of versions in turn, and keeps
+// what it was given in given.N files.
+func fakeEditor(t *testing.T, versions ...string) (dir string, restore func()) {
+	dir, err := ioutil.TempDir("", "record")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, v := range versions {
+		if err := ioutil.WriteFile(filepath.Join(dir, fmt.Sprintf("version.%d", i)), []byte(v), 0666); err != nil {
+			t.Fatal(err)
+		}
+	}
+	script := "#!/bin/sh\ncd \"" + dir + "\"\nn=$(ls given.* 2>/dev/null | wc -l)\ncp \"$1\" given.$n\n[ -f version.$n ] && cp version.$n \"$1\"\nexit 0\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, "editor"), []byte(script), 0777); err != nil {
+		t.Fatal(err)
+	}
+	restoreEnv := setenv(map[string]string{EnvVisual: "", EnvEditor: filepath.Join(dir, "editor")})
+	return dir, func() {
+		restoreEnv()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestUIRecordFormats(t *testing.T) {
+	fmt.Println("+ Testing UI/RecordFormat...")
+	assert := assert.New(t)
+
+	book := testBook{Title: "Dune", Year: 1965, Series: []string{"Dune"}}
+	for _, name := range []string{"yaml", "toml", "json"} {
+		format, err := ParseRecordFormat(name)
+		assert.Nil(err)
+		data, err := format.marshal(book)
+		assert.Nil(err)
+		assert.Contains(string(data), "Dune")
+		var parsed testBook
+		assert.Nil(format.unmarshal(data, &parsed))
+		assert.Equal(book, parsed)
+	}
+	_, err := ParseRecordFormat("xml")
+	assert.NotNil(err)
+
+	// error lines
+	for format, text := range map[RecordFormat]string{
+		RecordYAML: "title: Dune\nyear: [\n",
+		RecordTOML: "title = \"Dune\"\nyear = \n",
+		RecordJSON: "{\n  \"title\": \"Dune\",\n  \"year\": \"x\"\n}",
+	} {
+		var parsed testBook
+		err := format.unmarshal([]byte(text), &parsed)
+		assert.NotNil(err)
+		assert.True(errorLine([]byte(text), err) >= 2, err.Error())
+	}
+	assert.Equal(0, errorLine(nil, errors.New("no line")))
+	assert.Equal("a\n# error: oops\nb", annotate("a\nb", 1, errors.New("oops")))
+	assert.Equal("# error: oops\na\nb", annotate("a\nb", 0, errors.New("oops")))
+}
+
+func TestUIEditStruct(t *testing.T) {
+	fmt.Println("+ Testing UI/EditStruct()...")
+	assert := assert.New(t)
+	ui := &UI{}
+
+	// invalid, then failing validation, then valid
+	dir, restore := fakeEditor(t,
+		"title: Dune\nyear: [1965\n",
+		"title: Dune\nyear: 3000\nseries: [Dune]\n",
+		"title: Dune\nyear: 1965\nseries: [Dune]\n",
+	)
+	defer restore()
+	book := testBook{Title: "Dune"}
+	validate := func(v interface{}) error {
+		if v.(*testBook).Year > 2100 {
+			return errors.New("year is in the future")
+		}
+		return nil
+	}
+	assert.Nil(ui.EditStruct(&book, RecordYAML, validate))
+	assert.Equal(testBook{Title: "Dune", Year: 1965, Series: []string{"Dune"}}, book)
+	given, err := ioutil.ReadFile(filepath.Join(dir, "given.1"))
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(string(given), "title: Dune\nyear: [1965\n# error: yaml: line 2: "), string(given))
+	given, err = ioutil.ReadFile(filepath.Join(dir, "given.2"))
+	assert.Nil(err)
+	assert.True(strings.HasPrefix(string(given), "# error: year is in the future\n"), string(given))
+	restore()
+
+	// unchanged
+	_, restore = fakeEditor(t)
+	assert.Equal(ErrEditUnchanged, ui.EditStruct(&book, RecordJSON, nil))
+	restore()
+
+	// giving up after an error
+	_, restore = fakeEditor(t, "title = \"Dune\"\nyear = \n")
+	assert.NotNil(ui.EditStruct(&book, RecordTOML, nil))
+	assert.Equal(1965, book.Year)
+	restore()
+
+	// maps
+	_, restore = fakeEditor(t, `{"title": "Dune", "year": "1965"}`)
+	values := map[string]string{"title": "Dune"}
+	assert.Nil(ui.EditStruct(&values, RecordJSON, nil))
+	assert.Equal(map[string]string{"title": "Dune", "year": "1965"}, values)
+
+	assert.NotNil(ui.EditStruct(values, RecordJSON, nil))
+}
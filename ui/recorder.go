@@ -0,0 +1,543 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrSecretNotRecorded is returned when replaying a secret that was not
+// recorded.
+var ErrSecretNotRecorded = errors.New("secret not recorded")
+
+// recordedErrors are returned as is when replayed, so that callers can
+// compare them.
+var recordedErrors = []error{ErrNonInteractive, ErrNoEditor, ErrEditUnchanged, ErrInterrupted, ErrSecretNotRecorded}
+
+// Interaction is a prompt and the user answer, in a recorded session.
+type Interaction struct {
+	// Method of the UserInterface, such as "SelectOption".
+	Method string `json:"method"`
+	// Prompt is the question, title or field shown to the user.
+	Prompt string `json:"prompt,omitempty"`
+	// Options proposed to the user, without colour codes.
+	Options []string `json:"options,omitempty"`
+	// Current value, for UpdateValue and Edit.
+	Current string `json:"current,omitempty"`
+	// Answer of the user.
+	Answer string `json:"answer,omitempty"`
+	// Answers of the user, for SelectOptions.
+	Answers []string `json:"answers,omitempty"`
+	// Accepted is the answer to Accept.
+	Accepted bool `json:"accepted,omitempty"`
+	// Redacted answers were secrets, and were not recorded.
+	Redacted bool `json:"redacted,omitempty"`
+	// Error returned, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// String describes the prompt of the interaction.
+func (i Interaction) String() string {
+	if i.Method == "" {
+		return "end of session"
+	}
+	s := fmt.Sprintf("%s(%q)", i.Method, i.Prompt)
+	if len(i.Options) != 0 {
+		s += fmt.Sprintf(" with options %q", i.Options)
+	}
+	if i.Current != "" {
+		s += fmt.Sprintf(" with current value %q", i.Current)
+	}
+	return s
+}
+
+// matches checks if two interactions have the same prompt, options and
+// current value.
+func (i Interaction) matches(o Interaction) bool {
+	if i.Method != o.Method || i.Prompt != o.Prompt || i.Current != o.Current || len(i.Options) != len(o.Options) {
+		return false
+	}
+	for k := range i.Options {
+		if i.Options[k] != o.Options[k] {
+			return false
+		}
+	}
+	return true
+}
+
+// err returned by the recorded interaction, if any.
+func (i Interaction) err() error {
+	if i.Error == "" {
+		return nil
+	}
+	for _, e := range recordedErrors {
+		if e.Error() == i.Error {
+			return e
+		}
+	}
+	return errors.New(i.Error)
+}
+
+// DivergenceError is returned when a replayed session does not prompt the
+// user as in the recording.
+type DivergenceError struct {
+	// Index of the interaction in the recording.
+	Index int
+	// Expected interaction, with no method after the end of the recording.
+	Expected Interaction
+	// Got is the actual interaction, with no method if the session ended
+	// before the end of the recording.
+	Got Interaction
+}
+
+func (e *DivergenceError) Error() string {
+	return fmt.Sprintf("session diverges from the recording at interaction %d: expected %s, got %s", e.Index+1, e.Expected, e.Got)
+}
+
+// ReadInteractions reads a session recorded by a Recorder.
+func ReadInteractions(r io.Reader) ([]Interaction, error) {
+	var interactions []Interaction
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var i Interaction
+		if err := json.Unmarshal(scanner.Bytes(), &i); err != nil {
+			return interactions, fmt.Errorf("recording line %d: %w", line, err)
+		}
+		interactions = append(interactions, i)
+	}
+	return interactions, scanner.Err()
+}
+
+// errorString of an error, empty if nil.
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// plainOptions are options without colour codes, as recorded: options tagged
+// with Tag are only coloured on terminals.
+func plainOptions(options []string) []string {
+	if options == nil {
+		return nil
+	}
+	plain := make([]string, len(options))
+	for k, option := range options {
+		plain[k] = stripANSI(option)
+	}
+	return plain
+}
+
+// Recorder is a UserInterface writing every prompt and the user answer to a
+// recording, as JSON lines, to be replayed with a Replayer.
+// Secrets are not recorded, unless RecordSecrets is set.
+type Recorder struct {
+	UserInterface
+	// RecordSecrets also records the answers of GetSecret and GetNewSecret,
+	// in clear text.
+	RecordSecrets bool
+
+	mu     sync.Mutex
+	w      io.Writer
+	file   *os.File
+	prompt string
+}
+
+// NewRecorder records the session of ui to w.
+func NewRecorder(ui UserInterface, w io.Writer) *Recorder {
+	return &Recorder{UserInterface: ui, w: w}
+}
+
+// OpenRecorder records the session of ui to a file, truncating it.
+func OpenRecorder(ui UserInterface, path string) (*Recorder, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &Recorder{UserInterface: ui, w: f, file: f}, nil
+}
+
+// Close the recording file, if it was opened with OpenRecorder.
+func (r *Recorder) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	return r.file.Close()
+}
+
+// record an interaction, warning if it cannot be written.
+func (r *Recorder) record(i Interaction) {
+	data, err := json.Marshal(i)
+	if err == nil {
+		r.mu.Lock()
+		_, err = r.w.Write(append(data, '\n'))
+		r.mu.Unlock()
+	}
+	if err != nil {
+		r.Warningf("could not record interaction: %s", err.Error())
+	}
+}
+
+// Choice is shown, and remembered as the prompt of the next GetInput.
+func (r *Recorder) Choice(msg string, args ...interface{}) {
+	r.mu.Lock()
+	r.prompt = fmt.Sprintf(msg, args...)
+	r.mu.Unlock()
+	r.UserInterface.Choice(msg, args...)
+}
+
+// lastPrompt shown with Choice, forgotten once used.
+func (r *Recorder) lastPrompt() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	prompt := r.prompt
+	r.prompt = ""
+	return prompt
+}
+
+// T translates a message with the wrapped UserInterface.
+func (r *Recorder) T(msg string, args ...interface{}) string {
+	return sprintf(translate(r.UserInterface, msg), args...)
+}
+
+// TN translates a message depending on n with the wrapped UserInterface.
+func (r *Recorder) TN(singular, plural string, n int, args ...interface{}) string {
+	return sprintf(translatePlural(r.UserInterface, singular, plural, n), args...)
+}
+
+// YesNo returns the answers to yes/no questions of the wrapped UserInterface.
+func (r *Recorder) YesNo() (yes, no []string) {
+	return yesNo(r.UserInterface)
+}
+
+// GetInput from the user, and record it.
+func (r *Recorder) GetInput() (string, error) {
+	prompt := r.lastPrompt()
+	answer, err := r.UserInterface.GetInput()
+	r.record(Interaction{Method: "GetInput", Prompt: prompt, Answer: answer, Error: errorString(err)})
+	return answer, err
+}
+
+// GetSecret from the user, and record it if RecordSecrets is set.
+func (r *Recorder) GetSecret(prompt string) (string, error) {
+	secret, err := r.UserInterface.GetSecret(prompt)
+	r.recordSecret("GetSecret", prompt, secret, err)
+	return secret, err
+}
+
+// GetNewSecret from the user, and record it if RecordSecrets is set.
+func (r *Recorder) GetNewSecret(prompt string) (string, error) {
+	secret, err := r.UserInterface.GetNewSecret(prompt)
+	r.recordSecret("GetNewSecret", prompt, secret, err)
+	return secret, err
+}
+
+// recordSecret, or only the fact that it was asked for.
+func (r *Recorder) recordSecret(method, prompt, secret string, err error) {
+	i := Interaction{Method: method, Prompt: prompt, Error: errorString(err)}
+	if r.RecordSecrets {
+		i.Answer = secret
+	} else if err == nil {
+		i.Redacted = true
+	}
+	r.record(i)
+}
+
+// Accept asks the user, and records the answer.
+func (r *Recorder) Accept(question string) bool {
+	accepted := r.UserInterface.Accept(question)
+	r.record(Interaction{Method: "Accept", Prompt: question, Accepted: accepted})
+	return accepted
+}
+
+// AcceptDefault asks the user, and records the answer.
+func (r *Recorder) AcceptDefault(question string, defaultAnswer bool) bool {
+	accepted := r.UserInterface.AcceptDefault(question, defaultAnswer)
+	r.record(Interaction{Method: "AcceptDefault", Prompt: question, Accepted: accepted})
+	return accepted
+}
+
+// UpdateValue asks the user, and records the new value.
+func (r *Recorder) UpdateValue(field, usage, oldValue string, longField bool) (string, error) {
+	value, err := r.UserInterface.UpdateValue(field, usage, oldValue, longField)
+	r.record(Interaction{Method: "UpdateValue", Prompt: field, Current: oldValue, Answer: value, Error: errorString(err)})
+	return value, err
+}
+
+// SelectOption asks the user, and records the selected option.
+func (r *Recorder) SelectOption(title, usage string, options []string, longField bool) (string, error) {
+	recorded := plainOptions(options)
+	choice, err := r.UserInterface.SelectOption(title, usage, options, longField)
+	r.record(Interaction{Method: "SelectOption", Prompt: title, Options: recorded, Answer: choice, Error: errorString(err)})
+	return choice, err
+}
+
+// SelectFrom asks the user, and records the value of the selected option.
+func (r *Recorder) SelectFrom(title, usage string, options []Option, longField bool) (Option, error) {
+	choice, err := r.UserInterface.SelectFrom(title, usage, options, longField)
+	r.record(Interaction{Method: "SelectFrom", Prompt: title, Options: optionValues(options), Answer: choice.Value, Error: errorString(err)})
+	return choice, err
+}
+
+// SelectOptions asks the user, and records the selected options.
+func (r *Recorder) SelectOptions(title, usage string, options []string) ([]string, error) {
+	recorded := plainOptions(options)
+	selected, err := r.UserInterface.SelectOptions(title, usage, options)
+	r.record(Interaction{Method: "SelectOptions", Prompt: title, Options: recorded, Answers: selected, Error: errorString(err)})
+	return selected, err
+}
+
+// Edit with the user editor, and record the result.
+func (r *Recorder) Edit(oldValue string) (string, error) {
+	value, err := r.UserInterface.Edit(oldValue)
+	r.record(Interaction{Method: "Edit", Current: oldValue, Answer: value, Error: errorString(err)})
+	return value, err
+}
+
+// EditWithOptions with the user editor, and record the result.
+func (r *Recorder) EditWithOptions(oldValue string, opts EditOptions) (string, error) {
+	value, err := r.UserInterface.EditWithOptions(oldValue, opts)
+	r.record(Interaction{Method: "EditWithOptions", Current: oldValue, Answer: value, Error: errorString(err)})
+	return value, err
+}
+
+// EditStruct with the user editor, and record the result as JSON.
+func (r *Recorder) EditStruct(v interface{}, format RecordFormat, validate func(interface{}) error) error {
+	err := r.UserInterface.EditStruct(v, format, validate)
+	i := Interaction{Method: "EditStruct", Prompt: format.extension(), Error: errorString(err)}
+	if data, jsonErr := json.Marshal(v); jsonErr == nil {
+		i.Answer = string(data)
+	}
+	r.record(i)
+	return err
+}
+
+// Replayer is a UserInterface answering prompts from a recording instead of
+// asking the user, for example to reproduce a bug or as a regression test.
+// Output and logging go to the wrapped UserInterface.
+// If the prompts differ from the recording, input methods return a
+// *DivergenceError, and Accept returns false or the default answer.
+type Replayer struct {
+	UserInterface
+
+	mu           sync.Mutex
+	interactions []Interaction
+	next         int
+	prompt       string
+	divergence   *DivergenceError
+}
+
+// NewReplayer answers the prompts of ui with a recording read from r.
+func NewReplayer(ui UserInterface, r io.Reader) (*Replayer, error) {
+	interactions, err := ReadInteractions(r)
+	if err != nil {
+		return nil, err
+	}
+	return &Replayer{UserInterface: ui, interactions: interactions}, nil
+}
+
+// OpenReplayer answers the prompts of ui with a recording file.
+func OpenReplayer(ui UserInterface, path string) (*Replayer, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return NewReplayer(ui, f)
+}
+
+// Finish checks that the whole recording was replayed, without divergence,
+// and returns the first divergence otherwise.
+func (r *Replayer) Finish() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.divergence != nil {
+		return r.divergence
+	}
+	if r.next < len(r.interactions) {
+		return &DivergenceError{Index: r.next, Expected: r.interactions[r.next]}
+	}
+	return nil
+}
+
+// replay the next interaction, if it has the same prompt as got.
+// Nothing is consumed when the session diverges.
+func (r *Replayer) replay(got Interaction) (Interaction, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var expected Interaction
+	if r.next < len(r.interactions) {
+		expected = r.interactions[r.next]
+	}
+	if !expected.matches(got) {
+		err := &DivergenceError{Index: r.next, Expected: expected, Got: got}
+		if r.divergence == nil {
+			r.divergence = err
+		}
+		r.UserInterface.Errorf("%s", err.Error())
+		return Interaction{}, err
+	}
+	r.next++
+	r.UserInterface.Debugf("[replay] %s", got)
+	return expected, nil
+}
+
+// Choice is shown, and remembered as the prompt of the next GetInput.
+func (r *Replayer) Choice(msg string, args ...interface{}) {
+	r.mu.Lock()
+	r.prompt = fmt.Sprintf(msg, args...)
+	r.mu.Unlock()
+	r.UserInterface.Choice(msg, args...)
+}
+
+// T translates a message with the wrapped UserInterface.
+func (r *Replayer) T(msg string, args ...interface{}) string {
+	return sprintf(translate(r.UserInterface, msg), args...)
+}
+
+// TN translates a message depending on n with the wrapped UserInterface.
+func (r *Replayer) TN(singular, plural string, n int, args ...interface{}) string {
+	return sprintf(translatePlural(r.UserInterface, singular, plural, n), args...)
+}
+
+// YesNo returns the answers to yes/no questions of the wrapped UserInterface.
+func (r *Replayer) YesNo() (yes, no []string) {
+	return yesNo(r.UserInterface)
+}
+
+// GetInput from the recording.
+func (r *Replayer) GetInput() (string, error) {
+	r.mu.Lock()
+	prompt := r.prompt
+	r.prompt = ""
+	r.mu.Unlock()
+	i, err := r.replay(Interaction{Method: "GetInput", Prompt: prompt})
+	if err != nil {
+		return "", err
+	}
+	// shown as if it had been typed
+	r.UserInterface.Display(i.Answer + "\n")
+	return i.Answer, i.err()
+}
+
+// GetSecret from the recording.
+func (r *Replayer) GetSecret(prompt string) (string, error) {
+	return r.replaySecret("GetSecret", prompt)
+}
+
+// GetNewSecret from the recording.
+func (r *Replayer) GetNewSecret(prompt string) (string, error) {
+	return r.replaySecret("GetNewSecret", prompt)
+}
+
+// replaySecret, failing if it was not recorded.
+func (r *Replayer) replaySecret(method, prompt string) (string, error) {
+	i, err := r.replay(Interaction{Method: method, Prompt: prompt})
+	if err != nil {
+		return "", err
+	}
+	if i.Redacted {
+		return "", ErrSecretNotRecorded
+	}
+	return i.Answer, i.err()
+}
+
+// Accept from the recording, or false if the session diverges.
+func (r *Replayer) Accept(question string) bool {
+	i, err := r.replay(Interaction{Method: "Accept", Prompt: question})
+	return err == nil && i.Accepted
+}
+
+// AcceptDefault from the recording, or the default answer if the session
+// diverges.
+func (r *Replayer) AcceptDefault(question string, defaultAnswer bool) bool {
+	i, err := r.replay(Interaction{Method: "AcceptDefault", Prompt: question})
+	if err != nil {
+		return defaultAnswer
+	}
+	return i.Accepted
+}
+
+// UpdateValue from the recording.
+func (r *Replayer) UpdateValue(field, usage, oldValue string, longField bool) (string, error) {
+	i, err := r.replay(Interaction{Method: "UpdateValue", Prompt: field, Current: oldValue})
+	if err != nil {
+		return "", err
+	}
+	return i.Answer, i.err()
+}
+
+// SelectOption from the recording.
+func (r *Replayer) SelectOption(title, usage string, options []string, longField bool) (string, error) {
+	i, err := r.replay(Interaction{Method: "SelectOption", Prompt: title, Options: plainOptions(options)})
+	if err != nil {
+		return "", err
+	}
+	return i.Answer, i.err()
+}
+
+// SelectFrom the recording, returning the option with the recorded value.
+func (r *Replayer) SelectFrom(title, usage string, options []Option, longField bool) (Option, error) {
+	i, err := r.replay(Interaction{Method: "SelectFrom", Prompt: title, Options: optionValues(options)})
+	if err != nil {
+		return Option{}, err
+	}
+	if err := i.err(); err != nil || i.Answer == "" {
+		return Option{}, err
+	}
+	for _, o := range options {
+		if o.Value == i.Answer {
+			return o, nil
+		}
+	}
+	return Option{Value: i.Answer}, nil
+}
+
+// SelectOptions from the recording.
+func (r *Replayer) SelectOptions(title, usage string, options []string) ([]string, error) {
+	i, err := r.replay(Interaction{Method: "SelectOptions", Prompt: title, Options: plainOptions(options)})
+	if err != nil {
+		return nil, err
+	}
+	return i.Answers, i.err()
+}
+
+// Edit from the recording.
+func (r *Replayer) Edit(oldValue string) (string, error) {
+	i, err := r.replay(Interaction{Method: "Edit", Current: oldValue})
+	if err != nil {
+		return "", err
+	}
+	return i.Answer, i.err()
+}
+
+// EditWithOptions from the recording.
+func (r *Replayer) EditWithOptions(oldValue string, opts EditOptions) (string, error) {
+	i, err := r.replay(Interaction{Method: "EditWithOptions", Current: oldValue})
+	if err != nil {
+		return "", err
+	}
+	return i.Answer, i.err()
+}
+
+// EditStruct from the recording, setting v to the recorded value.
+func (r *Replayer) EditStruct(v interface{}, format RecordFormat, validate func(interface{}) error) error {
+	i, err := r.replay(Interaction{Method: "EditStruct", Prompt: format.extension()})
+	if err != nil {
+		return err
+	}
+	if err := i.err(); err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(i.Answer), v)
+}
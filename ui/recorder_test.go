@@ -0,0 +1,157 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// cliSession asks the questions of a small CLI.
+func cliSession(ui UserInterface) (string, []string, bool, error) {
+	name, err := Prompt(ui, "Name", ValidateNotEmpty)
+	if err != nil {
+		return "", nil, false, err
+	}
+	title, err := ui.SelectOption("title", "", []string{"Dune", "Dune Messiah"}, false)
+	if err != nil {
+		return "", nil, false, err
+	}
+	tags, err := ui.SelectOptions("tags", "", []string{"sf", "classic"})
+	if err != nil {
+		return "", nil, false, err
+	}
+	return name + "/" + title, tags, ui.Accept("Save?"), nil
+}
+
+func TestUIRecorder(t *testing.T) {
+	fmt.Println("+ Testing UI/Recorder...")
+	assert := assert.New(t)
+
+	var recording bytes.Buffer
+	ui := &UI{Color: ColorNever}
+	ui.SetInput(strings.NewReader("\nfrank\n2\n1\nc\ny\ny\n"))
+	r := NewRecorder(ui, &recording)
+	result, tags, saved, err := cliSession(r)
+	assert.Nil(err)
+	assert.Equal("frank/Dune Messiah", result)
+	assert.Equal([]string{"sf"}, tags)
+	assert.True(saved)
+
+	interactions, err := ReadInteractions(bytes.NewReader(recording.Bytes()))
+	assert.Nil(err)
+	assert.Equal(5, len(interactions))
+	assert.Equal(Interaction{Method: "GetInput", Prompt: "Name: "}, interactions[0])
+	assert.Equal(Interaction{Method: "SelectOption", Prompt: "title", Options: []string{"Dune", "Dune Messiah"}, Answer: "Dune Messiah"}, interactions[2])
+	assert.Equal(Interaction{Method: "Accept", Prompt: "Save?", Accepted: true}, interactions[4])
+
+	// replaying does not read any input
+	ui = &UI{Color: ColorNever}
+	ui.SetInput(strings.NewReader(""))
+	replayer, err := NewReplayer(ui, bytes.NewReader(recording.Bytes()))
+	assert.Nil(err)
+	result, tags, saved, err = cliSession(replayer)
+	assert.Nil(err)
+	assert.Equal("frank/Dune Messiah", result)
+	assert.Equal([]string{"sf"}, tags)
+	assert.True(saved)
+	assert.Nil(replayer.Finish())
+
+	// the session ends before the recording
+	replayer, err = NewReplayer(ui, bytes.NewReader(recording.Bytes()))
+	assert.Nil(err)
+	_, err = Prompt(replayer, "Name", nil)
+	assert.Nil(err)
+	err = replayer.Finish()
+	assert.NotNil(err)
+	divergence, ok := err.(*DivergenceError)
+	assert.True(ok)
+	assert.Equal(1, divergence.Index)
+	assert.Equal("", divergence.Got.Method)
+}
+
+func TestUIReplayerDivergence(t *testing.T) {
+	fmt.Println("+ Testing UI/Replayer divergence...")
+	assert := assert.New(t)
+
+	recording := `{"method":"SelectOption","prompt":"title","options":["Dune"],"answer":"Dune"}
+{"method":"GetSecret","prompt":"Password","redacted":true}
+{"method":"Edit","current":"Dune","error":"text unchanged"}
+`
+	ui := &UI{Color: ColorNever}
+	replayer, err := NewReplayer(ui, strings.NewReader(recording))
+	assert.Nil(err)
+
+	// different options
+	_, err = replayer.SelectOption("title", "", []string{"Dune", "Children of Dune"}, false)
+	divergence, ok := err.(*DivergenceError)
+	assert.True(ok)
+	assert.Equal(0, divergence.Index)
+	assert.Equal([]string{"Dune", "Children of Dune"}, divergence.Got.Options)
+	assert.False(replayer.Accept("Continue?"))
+	assert.True(replayer.AcceptDefault("Continue?", true))
+	assert.Equal(divergence, replayer.Finish(), "first divergence is kept")
+
+	// nothing was consumed
+	choice, err := replayer.SelectOption("title", "", []string{"Dune"}, false)
+	assert.Nil(err)
+	assert.Equal("Dune", choice)
+	_, err = replayer.GetSecret("Password")
+	assert.Equal(ErrSecretNotRecorded, err)
+	_, err = replayer.Edit("Dune")
+	assert.Equal(ErrEditUnchanged, err)
+
+	// after the end of the recording
+	_, err = replayer.GetInput()
+	divergence, ok = err.(*DivergenceError)
+	assert.True(ok)
+	assert.Equal(3, divergence.Index)
+	assert.Equal("", divergence.Expected.Method)
+
+	// the current value is part of the prompt
+	replayer, err = NewReplayer(ui, strings.NewReader(`{"method":"UpdateValue","prompt":"year","current":"1965","answer":"1966"}
+{"method":"Edit","current":"old","answer":"new"}
+`))
+	assert.Nil(err)
+	_, err = replayer.UpdateValue("year", "", "1969", false)
+	divergence, ok = err.(*DivergenceError)
+	assert.True(ok)
+	assert.Equal("1969", divergence.Got.Current)
+	value, err := replayer.UpdateValue("year", "", "1965", false)
+	assert.Nil(err)
+	assert.Equal("1966", value)
+	_, err = replayer.Edit("other")
+	assert.NotNil(err)
+	value, err = replayer.Edit("old")
+	assert.Nil(err)
+	assert.Equal("new", value)
+
+	_, err = NewReplayer(ui, strings.NewReader("not json\n"))
+	assert.NotNil(err)
+}
+
+func TestUIRecorderTaggedOptions(t *testing.T) {
+	fmt.Println("+ Testing UI/Recorder with tagged options...")
+	assert := assert.New(t)
+
+	// recorded on a terminal, replayed without colours
+	var recording bytes.Buffer
+	ui := &UI{Color: ColorAlways}
+	ui.SetInput(strings.NewReader("1\n"))
+	options := []string{ui.Tag("Dune", true), ui.Tag("Dune Messiah", false)}
+	assert.NotEqual(stripANSI(options[0]), options[0])
+	choice, err := NewRecorder(ui, &recording).SelectOption("title", "", options, false)
+	assert.Nil(err)
+	assert.Equal("Dune", choice)
+	assert.NotContains(recording.String(), "\\u001b")
+
+	ui = &UI{Color: ColorNever}
+	replayer, err := NewReplayer(ui, bytes.NewReader(recording.Bytes()))
+	assert.Nil(err)
+	choice, err = replayer.SelectOption("title", "", []string{ui.Tag("Dune", true), ui.Tag("Dune Messiah", false)}, false)
+	assert.Nil(err)
+	assert.Equal("Dune", choice)
+	assert.Nil(replayer.Finish())
+}
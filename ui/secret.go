@@ -0,0 +1,112 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+	"golang.org/x/term"
+)
+
+const secretsDoNotMatch = "Entries do not match, trying again."
+
+// GetSecret from user, such as a password, without echoing it.
+// If stdin is not a terminal, a line is read instead.
+func (ui UI) GetSecret(prompt string) (string, error) {
+	if _, auto := ui.automation(); auto {
+		ui.logDecision("%s: cannot get secret", prompt)
+		return "", ErrNonInteractive
+	}
+	ui.Choice("%s: ", prompt)
+	fd := int(os.Stdin.Fd())
+	if ui.input != nil || !term.IsTerminal(fd) {
+		ui.waitInput()
+		return ui.readSecret()
+	}
+
+	state, err := term.GetState(fd)
+	if err != nil {
+		return "", err
+	}
+	restore := func() {
+		term.Restore(fd, state)
+	}
+	// restore echo if interrupted, then let the signal do its job
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case s := <-signals:
+			restore()
+			fmt.Println()
+			signal.Stop(signals)
+			if p, err := os.FindProcess(os.Getpid()); err == nil {
+				p.Signal(s)
+			}
+		case <-done:
+		}
+	}()
+	defer func() {
+		signal.Stop(signals)
+		close(done)
+		if r := recover(); r != nil {
+			restore()
+			panic(r)
+		}
+	}()
+
+	// echo is disabled before waiting for input awaited in the background,
+	// and the secret is read from the shared reader, so that input typed
+	// ahead and already buffered is its start.
+	if err := disableEcho(fd); err != nil {
+		return "", err
+	}
+	ui.waitInput()
+	secret, err := ui.readSecret()
+	restore()
+	fmt.Println()
+	return secret, err
+}
+
+// readSecret reads a line, keeping spaces.
+func (ui UI) readSecret() (string, error) {
+	secret, err := ui.reader().ReadString('\n')
+	return strings.TrimRight(secret, "\r\n"), err
+}
+
+// disableEcho of a terminal, keeping line editing and signals.
+func disableEcho(fd int) error {
+	termios, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return err
+	}
+	termios.Lflag &^= unix.ECHO
+	termios.Lflag |= unix.ICANON | unix.ISIG
+	termios.Iflag |= unix.ICRNL
+	return unix.IoctlSetTermios(fd, ioctlSetTermios, termios)
+}
+
+// GetNewSecret from user, asking twice to confirm it.
+func (ui UI) GetNewSecret(prompt string) (string, error) {
+	for errs := 0; errs <= maxErrors; errs++ {
+		secret, err := ui.GetSecret(prompt)
+		if err != nil {
+			return "", err
+		}
+		confirmation, err := ui.GetSecret(ui.T(confirm) + " " + prompt)
+		if err != nil {
+			return "", err
+		}
+		if secret == confirmation {
+			return secret, nil
+		}
+		ui.Warning(ui.T(secretsDoNotMatch))
+	}
+	ui.Warning(ui.T(tooManyErrors))
+	return "", errors.New(invalidChoice)
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIGetSecret(t *testing.T) {
+	fmt.Println("+ Testing UI/GetSecret()...")
+	assert := assert.New(t)
+	ui := &UI{}
+
+	// not a terminal: reading lines, spaces are kept
+	ui.SetInput(strings.NewReader(" s3cr3t \r\n"))
+	secret, err := ui.GetSecret("API key")
+	assert.Nil(err)
+	assert.Equal(" s3cr3t ", secret)
+
+	ui.SetInput(strings.NewReader("one\ntwo\npassword\npassword\n"))
+	secret, err = ui.GetNewSecret("password")
+	assert.Nil(err)
+	assert.Equal("password", secret)
+
+	ui.SetInput(strings.NewReader("one\n"))
+	_, err = ui.GetNewSecret("password")
+	assert.NotNil(err)
+}
+
+func TestUIGetSecretAfterTimeout(t *testing.T) {
+	fmt.Println("+ Testing UI/GetSecret() after a timeout...")
+	assert := assert.New(t)
+	ui := &UI{Color: ColorNever}
+
+	r, w := io.Pipe()
+	ui.SetInput(r)
+	answer, err := ui.AcceptWithOptions("Continue?", AcceptOptions{Default: DefaultNo, Timeout: 20 * time.Millisecond})
+	assert.Nil(err)
+	assert.False(answer)
+
+	// the secret is not taken by the question that timed out
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		io.WriteString(w, "s3cr3t\n")
+	}()
+	secret, err := ui.GetSecret("Password")
+	assert.Nil(err)
+	assert.Equal("s3cr3t", secret)
+
+	// prompts are not format strings
+	ui.SetInput(strings.NewReader("x\n"))
+	secret, err = ui.GetSecret("100% secret")
+	assert.Nil(err)
+	assert.Equal("x", secret)
+}
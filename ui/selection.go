@@ -0,0 +1,146 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	toggleOptions     = "Toggle options [1-%d] (e.g. 1,3,5-7), select [all] or [none], [C]onfirm or [A]bort: "
+	invalidSelection  = "Invalid selection."
+	selectionAll      = "all"
+	selectionNone     = "none"
+	selectionSelected = "[x]"
+	selectionFree     = "[ ]"
+)
+
+// ParseSelection parses a list of option numbers and ranges, such as
+// "1,3,5-7", "all" or "none", for options numbered from 1 to max.
+// It returns the 0-based indexes, in the order they were given.
+func ParseSelection(input string, max int) ([]int, error) {
+	input = strings.ToLower(strings.TrimSpace(input))
+	switch input {
+	case selectionAll:
+		indexes := make([]int, max)
+		for i := range indexes {
+			indexes[i] = i
+		}
+		return indexes, nil
+	case selectionNone:
+		return []int{}, nil
+	}
+
+	var indexes []int
+	for _, part := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		bounds := strings.SplitN(part, "-", 2)
+		start, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid option %q", part)
+		}
+		end := start
+		if len(bounds) == 2 {
+			end, err = strconv.Atoi(bounds[1])
+			if err != nil || end < start {
+				return nil, fmt.Errorf("invalid range %q", part)
+			}
+		}
+		if start < 1 || end > max {
+			return nil, fmt.Errorf("option out of range: %q", part)
+		}
+		for i := start; i <= end; i++ {
+			indexes = append(indexes, i-1)
+		}
+	}
+	if len(indexes) == 0 {
+		return nil, errors.New(invalidSelection)
+	}
+	return indexes, nil
+}
+
+// SelectOptions among several, toggling them until the selection is confirmed.
+// Selected options are returned in their original order.
+// In non-interactive mode, only the option picked by the select policy is
+// returned: see Automation.
+func (ui UI) SelectOptions(title, usage string, options []string) ([]string, error) {
+	ui.SubPart(title)
+	if usage != "" {
+		fmt.Println(ui.Style(ui.theme().Usage, usage))
+	}
+	// the options of the caller are left as they are
+	options = append([]string(nil), options...)
+	RemoveDuplicates(&options)
+	if len(options) == 0 {
+		return []string{}, nil
+	}
+	if a, auto := ui.automation(); auto {
+		choice, err := ui.autoSelect(title, ui.toOptions(options), a.Select)
+		if err != nil {
+			return nil, err
+		}
+		return []string{choice.Value}, nil
+	}
+
+	selected := make([]bool, len(options))
+	errs := 0
+	for {
+		for i, o := range options {
+			mark := selectionFree
+			if selected[i] {
+				mark = selectionSelected
+			}
+			fmt.Printf("%d. %s %s\n", i+1, mark, o)
+		}
+		ui.Choice(ui.T(toggleOptions), len(options))
+		choice, scanErr := ui.GetInput()
+		if scanErr != nil {
+			return nil, scanErr
+		}
+
+		// translated keywords
+		if strings.EqualFold(choice, ui.T(selectionAll)) {
+			choice = selectionAll
+		} else if strings.EqualFold(choice, ui.T(selectionNone)) {
+			choice = selectionNone
+		}
+		switch {
+		case ui.isShortcut(choice, toggleOptions, "C"):
+			var result []string
+			for i, o := range options {
+				if selected[i] {
+					result = append(result, ui.unTag(o))
+				}
+			}
+			if ui.Accept(ui.TN(optionsSelected, "%d options selected: %s", len(result), len(result), strings.Join(result, ", "))) {
+				if result == nil {
+					result = []string{}
+				}
+				return result, nil
+			}
+			ui.Warning(ui.T(notConfirmed))
+			continue
+		case ui.isShortcut(choice, toggleOptions, "A"):
+			return nil, errors.New(userAborted)
+		case choice == selectionAll, choice == selectionNone:
+			for i := range selected {
+				selected[i] = strings.ToLower(choice) == selectionAll
+			}
+			continue
+		}
+
+		indexes, err := ParseSelection(choice, len(options))
+		if err != nil {
+			ui.Warning(ui.T(invalidChoice) + " " + err.Error())
+			errs++
+			if errs > maxErrors {
+				ui.Warning(ui.T(tooManyErrors))
+				return nil, errors.New(invalidChoice)
+			}
+			continue
+		}
+		for _, i := range indexes {
+			selected[i] = !selected[i]
+		}
+	}
+}
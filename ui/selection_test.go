@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// UI must implement UserInterface.
+var _ UserInterface = (*UI)(nil)
+
+func TestUIParseSelection(t *testing.T) {
+	fmt.Println("+ Testing UI/ParseSelection()...")
+	assert := assert.New(t)
+
+	indexes, err := ParseSelection("1,3, 5-7", 8)
+	assert.Nil(err)
+	assert.Equal([]int{0, 2, 4, 5, 6}, indexes)
+	indexes, err = ParseSelection("ALL", 3)
+	assert.Nil(err)
+	assert.Equal([]int{0, 1, 2}, indexes)
+	indexes, err = ParseSelection("none", 3)
+	assert.Nil(err)
+	assert.Equal([]int{}, indexes)
+
+	for _, invalid := range []string{"", "0", "4", "1-4", "3-1", "a", "1-b", ","} {
+		_, err = ParseSelection(invalid, 3)
+		assert.NotNil(err, "%q should be invalid", invalid)
+	}
+}
+
+func TestUISelectOptions(t *testing.T) {
+	fmt.Println("+ Testing UI/SelectOptions()...")
+	assert := assert.New(t)
+	ui := &UI{}
+	options := []string{"fantasy", "sf", "horror", "sf", "poetry"}
+
+	// toggle, confirm
+	ui.SetInput(strings.NewReader("1-3\n2,4\nc\ny\n"))
+	selected, err := ui.SelectOptions("Tags", "", options)
+	assert.Nil(err)
+	assert.Equal([]string{"fantasy", "horror", "poetry"}, selected)
+	assert.Equal([]string{"fantasy", "sf", "horror", "sf", "poetry"}, options, "options are not modified")
+
+	// all, then none, not confirmed, then one
+	ui.SetInput(strings.NewReader("all\nc\nn\nnone\n9\n2\nc\ny\n"))
+	selected, err = ui.SelectOptions("Tags", "", options)
+	assert.Nil(err)
+	assert.Equal([]string{"sf"}, selected)
+
+	// abort
+	ui.SetInput(strings.NewReader("1\na\n"))
+	_, err = ui.SelectOptions("Tags", "", options)
+	assert.NotNil(err)
+
+	// too many errors
+	ui.SetInput(strings.NewReader(strings.Repeat("x\n", maxErrors+1)))
+	_, err = ui.SelectOptions("Tags", "", options)
+	assert.NotNil(err)
+}
@@ -0,0 +1,215 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/mattn/go-runewidth"
+)
+
+const selectorHelp = "↑/↓ PgUp/PgDn to move, type to filter, Enter to pick, Esc to abort"
+
+// fuzzyScore checks if all characters of pattern appear in order in s,
+// regardless of case, and scores the match: consecutive characters and
+// characters at the start of words score higher.
+func fuzzyScore(pattern, s string) (int, bool) {
+	p := []rune(strings.ToLower(pattern))
+	t := []rune(strings.ToLower(s))
+	if len(p) == 0 {
+		return 0, true
+	}
+	score, pi, last := 0, 0, -1
+	for i, c := range t {
+		if pi == len(p) {
+			break
+		}
+		if c != p[pi] {
+			continue
+		}
+		score++
+		if last >= 0 && last == i-1 {
+			score += 5
+		}
+		if i == 0 || !unicode.IsLetter(t[i-1]) && !unicode.IsDigit(t[i-1]) {
+			score += 3
+		}
+		last = i
+		pi++
+	}
+	if pi < len(p) {
+		return 0, false
+	}
+	return score, true
+}
+
+// selector is the state of the full-screen selector.
+type selector struct {
+	options []string
+	filter  []rune
+	// matches are indexes of options matching the filter, best first.
+	matches []int
+	cursor  int
+	offset  int
+	height  int
+	drawn   int
+}
+
+func newSelector(options []string, height int) *selector {
+	if height < 1 {
+		height = 1
+	}
+	s := &selector{options: options, height: height}
+	s.update()
+	return s
+}
+
+// update matches after the filter has changed.
+func (s *selector) update() {
+	type match struct{ index, score int }
+	var matches []match
+	for i, o := range s.options {
+		if score, ok := fuzzyScore(string(s.filter), o); ok {
+			matches = append(matches, match{i, score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].score > matches[j].score })
+	s.matches = make([]int, len(matches))
+	for i, m := range matches {
+		s.matches[i] = m.index
+	}
+	s.cursor = 0
+	s.offset = 0
+}
+
+// move the cursor by delta lines, scrolling if necessary.
+func (s *selector) move(delta int) {
+	s.cursor += delta
+	if s.cursor >= len(s.matches) {
+		s.cursor = len(s.matches) - 1
+	}
+	if s.cursor < 0 {
+		s.cursor = 0
+	}
+	if s.cursor < s.offset {
+		s.offset = s.cursor
+	}
+	if s.cursor >= s.offset+s.height {
+		s.offset = s.cursor - s.height + 1
+	}
+}
+
+// handle a key press. It returns true once an option is chosen, with its
+// index, or an error if the user aborted.
+func (s *selector) handle(k keyPress) (bool, int, error) {
+	switch k.key {
+	case keyEnter:
+		if len(s.matches) == 0 {
+			return false, -1, nil
+		}
+		return true, s.matches[s.cursor], nil
+	case keyEscape, keyInterrupt, keyEOF:
+		return true, -1, errors.New(userAborted)
+	case keyUp:
+		s.move(-1)
+	case keyDown, keyTab:
+		s.move(1)
+	case keyPageUp:
+		s.move(-s.height)
+	case keyPageDown:
+		s.move(s.height)
+	case keyHome:
+		s.move(-len(s.matches))
+	case keyEnd:
+		s.move(len(s.matches))
+	case keyBackspace:
+		if len(s.filter) != 0 {
+			s.filter = s.filter[:len(s.filter)-1]
+			s.update()
+		}
+	case keyKillToStart:
+		s.filter = nil
+		s.update()
+	case keyRune:
+		s.filter = append(s.filter, k.char)
+		s.update()
+	}
+	return false, -1, nil
+}
+
+// render the selector, replacing what was previously drawn.
+func (s *selector) render(w io.Writer, ui UI, title string, width int) {
+	lines := []string{
+		ui.Style(ui.theme().SubPart, title),
+		ui.Style(ui.theme().Choice, "> ") + string(s.filter),
+	}
+	for i := s.offset; i < len(s.matches) && i < s.offset+s.height; i++ {
+		option := runewidth.Truncate(strings.Replace(s.options[s.matches[i]], "\n", " ", -1), width-2, "…")
+		if i == s.cursor {
+			lines = append(lines, ui.Style(ui.theme().Choice, "> "+option))
+		} else {
+			lines = append(lines, "  "+option)
+		}
+	}
+	lines = append(lines, ui.Style(ui.theme().Usage, runewidth.Truncate(fmt.Sprintf("[%d/%d] %s", len(s.matches), len(s.options), ui.T(selectorHelp)), width, "…")))
+	s.clear(w)
+	fmt.Fprint(w, strings.Join(lines, "\r\n"))
+	s.drawn = len(lines)
+}
+
+// clear what was drawn.
+func (s *selector) clear(w io.Writer) {
+	if s.drawn > 1 {
+		fmt.Fprintf(w, "\033[%dA", s.drawn-1)
+	}
+	fmt.Fprint(w, "\r\033[J")
+	s.drawn = 0
+}
+
+// Pick an option with a full-screen selector: arrow keys and page up/down to
+// move, typing to filter options with fuzzy matching, Enter to pick and Esc to
+// abort.
+// If the terminal is not interactive, it falls back to SelectOption.
+func (ui UI) Pick(title string, options []string) (string, error) {
+	// the options of the caller are left as they are
+	options = append([]string(nil), options...)
+	RemoveDuplicates(&options)
+	if _, auto := ui.automation(); auto || !IsInteractive() || ui.input != nil {
+		return ui.SelectOption(title, "", options, false)
+	}
+	restore, err := makeRaw(os.Stdin)
+	if err != nil {
+		return ui.SelectOption(title, "", options, false)
+	}
+	defer restore()
+
+	width, height := terminalSize()
+	s := newSelector(options, height-3)
+	out := os.Stdout
+	// hide cursor while selecting
+	fmt.Fprint(out, "\033[?25l")
+	defer fmt.Fprint(out, "\033[?25h")
+	for {
+		s.render(out, ui, title, width)
+		ui.waitInput()
+		k, err := readKey(ui.reader())
+		if err != nil {
+			s.clear(out)
+			return "", err
+		}
+		done, index, err := s.handle(k)
+		if !done {
+			continue
+		}
+		s.clear(out)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(out, "%s %s\r\n", ui.Style(ui.theme().SubPart, title+":"), options[index])
+		return ui.unTag(options[index]), nil
+	}
+}
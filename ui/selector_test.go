@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUIReadKey(t *testing.T) {
+	fmt.Println("+ Testing UI/readKey()...")
+	assert := assert.New(t)
+
+	r := bufio.NewReader(strings.NewReader("a\x1b[A\x1b[6~\x1bOH\r\x7fé\x03\x1b[99z"))
+	expected := []keyPress{
+		{key: keyRune, char: 'a'},
+		{key: keyUp},
+		{key: keyPageDown},
+		{key: keyHome},
+		{key: keyEnter},
+		{key: keyBackspace},
+		{key: keyRune, char: 'é'},
+		{key: keyInterrupt},
+		{key: keyUnknown},
+	}
+	for _, e := range expected {
+		k, err := readKey(r)
+		assert.Nil(err)
+		assert.Equal(e, k)
+	}
+	_, err := readKey(r)
+	assert.NotNil(err)
+
+	// escape alone
+	r = bufio.NewReader(strings.NewReader("\x1b"))
+	k, err := readKey(r)
+	assert.Nil(err)
+	assert.Equal(keyEscape, k.key)
+}
+
+func TestUIFuzzyScore(t *testing.T) {
+	fmt.Println("+ Testing UI/fuzzyScore()...")
+	assert := assert.New(t)
+
+	_, ok := fuzzyScore("", "anything")
+	assert.True(ok)
+	_, ok = fuzzyScore("xyz", "Victor Hugo")
+	assert.False(ok)
+	consecutive, ok := fuzzyScore("hug", "Victor Hugo")
+	assert.True(ok)
+	scattered, ok := fuzzyScore("hug", "Heinrich Ungerer")
+	assert.True(ok)
+	assert.True(consecutive > scattered)
+}
+
+func TestUISelector(t *testing.T) {
+	fmt.Println("+ Testing UI/selector...")
+	assert := assert.New(t)
+
+	options := []string{"Stendhal", "Victor Hugo", "Honoré de Balzac", "Gustave Flaubert", "Émile Zola"}
+	s := newSelector(options, 2)
+	assert.Equal([]int{0, 1, 2, 3, 4}, s.matches)
+
+	// moving and scrolling
+	s.handle(keyPress{key: keyDown})
+	s.handle(keyPress{key: keyDown})
+	assert.Equal(2, s.cursor)
+	assert.Equal(1, s.offset)
+	s.handle(keyPress{key: keyPageDown})
+	assert.Equal(4, s.cursor)
+	s.handle(keyPress{key: keyHome})
+	assert.Equal(0, s.cursor)
+	assert.Equal(0, s.offset)
+
+	// filtering
+	for _, c := range "ho" {
+		s.handle(keyPress{key: keyRune, char: c})
+	}
+	assert.Equal([]int{2, 1}, s.matches, "best match should be first")
+	s.handle(keyPress{key: keyBackspace})
+	s.handle(keyPress{key: keyBackspace})
+	s.handle(keyPress{key: keyRune, char: 'z'})
+	assert.Equal([]int{4, 2}, s.matches)
+
+	buffer := &bytes.Buffer{}
+	s.render(buffer, UI{Color: ColorNever}, "Author", 80)
+	assert.Equal("\r\x1b[JAuthor\r\n> z\r\n> Émile Zola\r\n  Honoré de Balzac\r\n[2/5] "+selectorHelp, buffer.String())
+
+	done, index, err := s.handle(keyPress{key: keyEnter})
+	assert.True(done)
+	assert.Equal(4, index)
+	assert.Nil(err)
+
+	// no match
+	s.handle(keyPress{key: keyRune, char: 'x'})
+	done, _, _ = s.handle(keyPress{key: keyEnter})
+	assert.False(done)
+	done, _, err = s.handle(keyPress{key: keyEscape})
+	assert.True(done)
+	assert.NotNil(err)
+}
+
+func TestUIPickFallback(t *testing.T) {
+	fmt.Println("+ Testing UI/Pick() fallback...")
+	ui := &UI{}
+	ui.SetInput(strings.NewReader("2\n"))
+	choice, err := ui.Pick("Author", []string{"Stendhal", "Victor Hugo"})
+	assert.Nil(t, err)
+	assert.Equal(t, "Victor Hugo", choice)
+
+	options := []string{"Stendhal", "Zola", "Stendhal", "Victor Hugo"}
+	ui.SetInput(strings.NewReader("3\n"))
+	choice, err = ui.Pick("Author", options)
+	assert.Nil(t, err)
+	assert.Equal(t, "Victor Hugo", choice)
+	assert.Equal(t, []string{"Stendhal", "Zola", "Stendhal", "Victor Hugo"}, options, "options are not modified")
+}
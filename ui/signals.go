@@ -0,0 +1,118 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"golang.org/x/term"
+)
+
+// cleanup hook, compared by address when unregistering.
+type cleanupHook struct {
+	f func()
+}
+
+var (
+	cleanupMu    sync.Mutex
+	cleanupHooks []*cleanupHook
+	// exit is replaced in tests.
+	exit = os.Exit
+)
+
+// RegisterCleanup adds a function to call if the program is interrupted by a
+// signal handled by HandleSignals. Functions are called in reverse order of
+// registration. The returned function unregisters it, for instance once a
+// spinner is stopped or a temporary file removed.
+func RegisterCleanup(f func()) (unregister func()) {
+	h := &cleanupHook{f: f}
+	cleanupMu.Lock()
+	cleanupHooks = append(cleanupHooks, h)
+	cleanupMu.Unlock()
+	return func() {
+		cleanupMu.Lock()
+		defer cleanupMu.Unlock()
+		for i, other := range cleanupHooks {
+			if other == h {
+				cleanupHooks = append(cleanupHooks[:i], cleanupHooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// RunCleanups calls and unregisters all cleanup functions, the most recently
+// registered first.
+func RunCleanups() {
+	runCleanups()
+}
+
+// runCleanups and return how many were called.
+func runCleanups() int {
+	cleanupMu.Lock()
+	hooks := cleanupHooks
+	cleanupHooks = nil
+	cleanupMu.Unlock()
+	for i := len(hooks) - 1; i >= 0; i-- {
+		hooks[i].f()
+	}
+	return len(hooks)
+}
+
+// HandleSignals catches SIGINT, SIGTERM and SIGHUP, and then:
+// restores the terminal as it was when HandleSignals was called, shows the
+// cursor, calls the cleanup functions (which finalize spinners and progress
+// lines), closes the log file, and exits with code 128 + the signal number.
+// The returned function stops handling signals.
+func (ui *UI) HandleSignals() (stop func()) {
+	var restoreTerminal func()
+	fd := int(os.Stdin.Fd())
+	if state, err := term.GetState(fd); err == nil {
+		restoreTerminal = func() {
+			term.Restore(fd, state)
+		}
+	}
+
+	signals := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+	go func() {
+		select {
+		case s := <-signals:
+			ui.interrupted(s, restoreTerminal)
+		case <-done:
+		}
+	}()
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(done)
+		})
+	}
+}
+
+// interrupted cleans up after a signal, and exits.
+func (ui *UI) interrupted(s os.Signal, restoreTerminal func()) {
+	if restoreTerminal != nil {
+		restoreTerminal()
+	}
+	terminal := IsTerminal(os.Stdout)
+	if terminal {
+		// show the cursor
+		fmt.Print("\033[?25h")
+	}
+	if runCleanups() == 0 && terminal {
+		// nothing was finalized, do not leave the cursor after ^C
+		fmt.Println()
+	}
+	ui.Debugf("interrupted by %s", s.String())
+	ui.CloseLog()
+	code := 1
+	if signo, ok := s.(syscall.Signal); ok {
+		code = 128 + int(signo)
+	}
+	exit(code)
+}
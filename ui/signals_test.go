@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"fmt"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUICleanups(t *testing.T) {
+	fmt.Println("+ Testing UI/RegisterCleanup()...")
+	assert := assert.New(t)
+
+	var calls []int
+	RegisterCleanup(func() { calls = append(calls, 1) })
+	unregister := RegisterCleanup(func() { calls = append(calls, 2) })
+	RegisterCleanup(func() { calls = append(calls, 3) })
+	unregister()
+	unregister()
+	RunCleanups()
+	assert.Equal([]int{3, 1}, calls)
+	// hooks are only called once
+	RunCleanups()
+	assert.Equal([]int{3, 1}, calls)
+}
+
+func TestUIHandleSignals(t *testing.T) {
+	fmt.Println("+ Testing UI/HandleSignals()...")
+	assert := assert.New(t)
+	defer func(f func(int)) { exit = f }(exit)
+	codes := make(chan int, 1)
+	exit = func(code int) { codes <- code }
+
+	// direct call
+	ui := &UI{}
+	restored, cleaned := false, false
+	RegisterCleanup(func() { cleaned = true })
+	ui.interrupted(syscall.SIGTERM, func() { restored = true })
+	assert.Equal(143, <-codes)
+	assert.True(restored)
+	assert.True(cleaned)
+
+	// real signal
+	cleaned = false
+	RegisterCleanup(func() { cleaned = true })
+	stop := ui.HandleSignals()
+	defer stop()
+	assert.Nil(syscall.Kill(syscall.Getpid(), syscall.SIGHUP))
+	select {
+	case code := <-codes:
+		assert.Equal(129, code)
+		assert.True(cleaned)
+	case <-time.After(time.Second):
+		assert.Fail("signal not handled")
+	}
+	stop()
+}
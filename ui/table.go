@@ -0,0 +1,389 @@
+package ui
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// Alignment of a table column.
+type Alignment int
+
+// Column alignments.
+const (
+	AlignLeft Alignment = iota
+	AlignRight
+	AlignCenter
+)
+
+// TableFormat is an output format for tables.
+type TableFormat int
+
+// Table formats.
+const (
+	TableText TableFormat = iota
+	TableCSV
+	TableTSV
+	TableMarkdown
+	TableJSON
+)
+
+var tableFormats = map[string]TableFormat{
+	"text":     TableText,
+	"csv":      TableCSV,
+	"tsv":      TableTSV,
+	"markdown": TableMarkdown,
+	"md":       TableMarkdown,
+	"json":     TableJSON,
+}
+
+// ParseTableFormat returns the format for a name (text, csv, tsv, markdown, md
+// or json).
+func ParseTableFormat(name string) (TableFormat, error) {
+	format, ok := tableFormats[strings.ToLower(name)]
+	if !ok {
+		return TableText, fmt.Errorf("unknown table format %q", name)
+	}
+	return format, nil
+}
+
+// Table of strings, which can be rendered as aligned text or exported.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+	// Align defines the alignment of each column in text output, AlignLeft
+	// by default.
+	Align []Alignment
+	// MaxWidths defines the maximum width of each column in text output,
+	// 0 meaning no limit.
+	MaxWidths []int
+	// Wrap long cells on several lines instead of truncating them.
+	Wrap bool
+}
+
+// NewTable with the given headers.
+func NewTable(headers ...string) *Table {
+	return &Table{Headers: headers}
+}
+
+// AddRow to the table.
+// Missing cells are left empty, extra cells add columns without headers.
+func (t *Table) AddRow(cells ...string) {
+	row := make([]string, len(t.Headers))
+	copy(row, cells)
+	if len(cells) > len(row) {
+		row = append(row, cells[len(row):]...)
+	}
+	t.Rows = append(t.Rows, row)
+}
+
+// cellAt returns the value of a column in a row, empty if the row is short.
+func cellAt(row []string, column int) string {
+	if column < len(row) {
+		return row[column]
+	}
+	return ""
+}
+
+// columns of the table: headers are optional, and rows can be longer.
+func (t *Table) columns() int {
+	columns := len(t.Headers)
+	for _, r := range t.Rows {
+		if len(r) > columns {
+			columns = len(r)
+		}
+	}
+	return columns
+}
+
+// padded returns cells with exactly one cell per column, missing cells being
+// empty.
+func padded(cells []string, columns int) []string {
+	row := make([]string, columns)
+	copy(row, cells)
+	return row
+}
+
+// headers with one header per column, empty for columns without one.
+func (t *Table) headers() []string {
+	return padded(t.Headers, t.columns())
+}
+
+// rows with exactly one cell per column.
+func (t *Table) rows() [][]string {
+	columns := t.columns()
+	rows := make([][]string, len(t.Rows))
+	for i, r := range t.Rows {
+		rows[i] = padded(r, columns)
+	}
+	return rows
+}
+
+// SortBy sorts rows by the values in a column.
+// Values are compared as numbers if both are numbers, or as case-insensitive
+// strings otherwise. The sort is stable.
+func (t *Table) SortBy(column int, descending bool) error {
+	if column < 0 || column >= t.columns() {
+		return errors.New("invalid column")
+	}
+	sort.SliceStable(t.Rows, func(i, j int) bool {
+		a, b := cellAt(t.Rows[i], column), cellAt(t.Rows[j], column)
+		if descending {
+			a, b = b, a
+		}
+		fa, errA := strconv.ParseFloat(a, 64)
+		fb, errB := strconv.ParseFloat(b, 64)
+		if errA == nil && errB == nil {
+			return fa < fb
+		}
+		return strings.ToLower(a) < strings.ToLower(b)
+	})
+	return nil
+}
+
+// Format the table.
+// For text output, headers are passed through headerStyle if it is not nil.
+func (t *Table) Format(format TableFormat, headerStyle func(string) string) (string, error) {
+	switch format {
+	case TableText:
+		return t.text(headerStyle, "─"), nil
+	case TableCSV:
+		return t.csv(',')
+	case TableTSV:
+		return t.csv('\t')
+	case TableMarkdown:
+		return t.markdown(), nil
+	case TableJSON:
+		return t.json()
+	}
+	return "", errors.New("unknown table format")
+}
+
+// cell returns the lines of a cell, truncated or wrapped to width.
+func (t *Table) cell(value string, width int) []string {
+	var lines []string
+	for _, line := range strings.Split(value, "\n") {
+		switch {
+		case width <= 0 || runewidth.StringWidth(line) <= width:
+			lines = append(lines, line)
+		case t.Wrap:
+			lines = append(lines, wrapText(line, width)...)
+		default:
+			lines = append(lines, runewidth.Truncate(line, width, "…"))
+		}
+	}
+	return lines
+}
+
+// pad a string to width, according to alignment.
+func pad(s string, width int, align Alignment) string {
+	switch align {
+	case AlignRight:
+		return runewidth.FillLeft(s, width)
+	case AlignCenter:
+		left := (width - runewidth.StringWidth(s)) / 2
+		if left > 0 {
+			s = strings.Repeat(" ", left) + s
+		}
+	}
+	return runewidth.FillRight(s, width)
+}
+
+// text output, with aligned columns, and headers if there are any.
+func (t *Table) text(headerStyle func(string) string, separator string) string {
+	columns := t.columns()
+	if columns == 0 {
+		return ""
+	}
+	hasHeaders := len(t.Headers) != 0
+	rows := t.rows()
+	if hasHeaders {
+		rows = append([][]string{t.headers()}, rows...)
+	}
+	// split cells into lines, and compute the width of each column
+	widths := make([]int, columns)
+	cells := make([][][]string, len(rows))
+	for i, row := range rows {
+		cells[i] = make([][]string, columns)
+		for j := 0; j < columns; j++ {
+			maxWidth := 0
+			if j < len(t.MaxWidths) {
+				maxWidth = t.MaxWidths[j]
+			}
+			cells[i][j] = t.cell(row[j], maxWidth)
+			for _, line := range cells[i][j] {
+				if w := runewidth.StringWidth(line); w > widths[j] {
+					widths[j] = w
+				}
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	for i, row := range cells {
+		height := 1
+		for _, lines := range row {
+			if len(lines) > height {
+				height = len(lines)
+			}
+		}
+		for l := 0; l < height; l++ {
+			parts := make([]string, len(row))
+			for j, lines := range row {
+				line := ""
+				if l < len(lines) {
+					line = lines[l]
+				}
+				align := AlignLeft
+				if j < len(t.Align) {
+					align = t.Align[j]
+				}
+				parts[j] = pad(line, widths[j], align)
+				if hasHeaders && i == 0 && headerStyle != nil {
+					parts[j] = headerStyle(parts[j])
+				}
+			}
+			out.WriteString(strings.TrimRight(strings.Join(parts, "  "), " ") + "\n")
+		}
+		if hasHeaders && i == 0 {
+			total := 0
+			for _, w := range widths {
+				total += w
+			}
+			total += 2 * (len(widths) - 1)
+			out.WriteString(strings.Repeat(separator, total) + "\n")
+		}
+	}
+	return out.String()
+}
+
+// csv output, with a given separator, and headers if there are any.
+func (t *Table) csv(separator rune) (string, error) {
+	var out bytes.Buffer
+	w := csv.NewWriter(&out)
+	w.Comma = separator
+	if len(t.Headers) != 0 {
+		if err := w.Write(t.headers()); err != nil {
+			return "", err
+		}
+	}
+	if err := w.WriteAll(t.rows()); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// markdown output.
+func (t *Table) markdown() string {
+	escape := func(s string) string {
+		s = strings.Replace(s, "|", "\\|", -1)
+		return strings.Replace(s, "\n", "<br>", -1)
+	}
+	row := func(cells []string) string {
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = escape(c)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |\n"
+	}
+	headers := t.headers()
+	separators := make([]string, len(headers))
+	for i := range headers {
+		align := AlignLeft
+		if i < len(t.Align) {
+			align = t.Align[i]
+		}
+		switch align {
+		case AlignRight:
+			separators[i] = "---:"
+		case AlignCenter:
+			separators[i] = ":---:"
+		default:
+			separators[i] = "---"
+		}
+	}
+	out := row(headers) + "|" + strings.Join(separators, "|") + "|\n"
+	for _, r := range t.rows() {
+		out += row(r)
+	}
+	return out
+}
+
+// json output, as a list of objects indexed by headers, or by column number
+// for columns without header.
+func (t *Table) json() (string, error) {
+	headers := t.headers()
+	for i, h := range headers {
+		if h == "" {
+			headers[i] = strconv.Itoa(i + 1)
+		}
+	}
+	objects := []map[string]string{}
+	for _, r := range t.rows() {
+		o := make(map[string]string)
+		for i, h := range headers {
+			o[h] = r[i]
+		}
+		objects = append(objects, o)
+	}
+	out, err := json.MarshalIndent(objects, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(out) + "\n", nil
+}
+
+// wrapText on word boundaries so that each line fits in width, breaking words
+// that are too long.
+func wrapText(s string, width int) []string {
+	var lines []string
+	current := ""
+	for _, word := range strings.Fields(s) {
+		for runewidth.StringWidth(word) > width {
+			if current != "" {
+				lines = append(lines, current)
+				current = ""
+			}
+			head := runewidth.Truncate(word, width, "")
+			if head == "" {
+				// a single character wider than the column
+				head = string([]rune(word)[:1])
+			}
+			lines = append(lines, head)
+			word = word[len(head):]
+		}
+		switch {
+		case current == "":
+			current = word
+		case runewidth.StringWidth(current)+1+runewidth.StringWidth(word) <= width:
+			current += " " + word
+		default:
+			lines = append(lines, current)
+			current = word
+		}
+	}
+	if current != "" || len(lines) == 0 {
+		lines = append(lines, current)
+	}
+	return lines
+}
+
+// Table renders a table in the given format, with headers styled like titles.
+// The result can be passed to Display.
+func (ui UI) Table(t *Table, format TableFormat) (string, error) {
+	if format == TableText {
+		separator := "─"
+		if ui.theme().ASCII {
+			separator = "-"
+		}
+		return t.text(func(h string) string { return ui.Style(ui.theme().Title, h) }, separator), nil
+	}
+	return t.Format(format, nil)
+}
@@ -0,0 +1,140 @@
+package ui
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUITable(t *testing.T) {
+	fmt.Println("+ Testing UI/Table...")
+	assert := assert.New(t)
+
+	table := NewTable("Title", "Author", "Year")
+	table.AddRow("Le Rouge et le Noir", "Stendhal", "1830")
+	table.AddRow("Les Misérables", "Victor Hugo", "1862")
+	table.AddRow("三国演义", "罗贯中", "1522")
+	table.Align = []Alignment{AlignLeft, AlignLeft, AlignRight}
+
+	assert.NotNil(table.SortBy(3, false))
+	assert.Nil(table.SortBy(2, false))
+	text, err := table.Format(TableText, nil)
+	assert.Nil(err)
+	expected := `Title                Author       Year
+──────────────────────────────────────
+三国演义             罗贯中       1522
+Le Rouge et le Noir  Stendhal     1830
+Les Misérables       Victor Hugo  1862
+`
+	assert.Equal(expected, text)
+
+	// truncating and wrapping
+	table.MaxWidths = []int{10}
+	assert.Nil(table.SortBy(0, true))
+	text, err = table.Format(TableText, nil)
+	assert.Nil(err)
+	expected = `Title       Author       Year
+─────────────────────────────
+三国演义    罗贯中       1522
+Les Misér…  Victor Hugo  1862
+Le Rouge …  Stendhal     1830
+`
+	assert.Equal(expected, text)
+	table.Wrap = true
+	text, err = table.Format(TableText, nil)
+	assert.Nil(err)
+	expected = `Title       Author       Year
+─────────────────────────────
+三国演义    罗贯中       1522
+Les         Victor Hugo  1862
+Misérables
+Le Rouge    Stendhal     1830
+et le Noir
+`
+	assert.Equal(expected, text)
+	assert.Equal([]string{"abc", "def", "g h"}, wrapText("abcdefg h", 3))
+
+	// other formats
+	table = NewTable("Title", "Year")
+	table.AddRow("Pipe | and, comma", "2017")
+	out, err := table.Format(TableCSV, nil)
+	assert.Nil(err)
+	assert.Equal("Title,Year\n\"Pipe | and, comma\",2017\n", out)
+	out, err = table.Format(TableTSV, nil)
+	assert.Nil(err)
+	assert.Equal("Title\tYear\nPipe | and, comma\t2017\n", out)
+	out, err = table.Format(TableMarkdown, nil)
+	assert.Nil(err)
+	assert.Equal("| Title | Year |\n|---|---|\n| Pipe \\| and, comma | 2017 |\n", out)
+	out, err = table.Format(TableJSON, nil)
+	assert.Nil(err)
+	assert.Equal("[\n  {\n    \"Title\": \"Pipe | and, comma\",\n    \"Year\": \"2017\"\n  }\n]\n", out)
+
+	format, err := ParseTableFormat("MD")
+	assert.Nil(err)
+	assert.Equal(TableMarkdown, format)
+	_, err = ParseTableFormat("xml")
+	assert.NotNil(err)
+
+	// header styling
+	ui := &UI{Color: ColorAlways}
+	out, err = ui.Table(table, TableText)
+	assert.Nil(err)
+	assert.Contains(out, ui.GreenBold("Title            "))
+}
+
+func TestUITableIncomplete(t *testing.T) {
+	fmt.Println("+ Testing UI/Table with missing cells...")
+	assert := assert.New(t)
+
+	// no headers
+	noHeaders := &Table{Rows: [][]string{{"a"}, {"bb", "c"}}}
+	for _, format := range []TableFormat{TableText, TableCSV, TableTSV, TableMarkdown, TableJSON} {
+		_, err := noHeaders.Format(format, nil)
+		assert.Nil(err)
+	}
+	text, err := noHeaders.Format(TableText, nil)
+	assert.Nil(err)
+	assert.Equal("a\nbb  c\n", text)
+	out, err := noHeaders.Format(TableCSV, nil)
+	assert.Nil(err)
+	assert.Equal("a,\nbb,c\n", out)
+	out, err = noHeaders.Format(TableMarkdown, nil)
+	assert.Nil(err)
+	assert.Equal("|  |  |\n|---|---|\n| a |  |\n| bb | c |\n", out)
+	text, err = (&Table{}).Format(TableText, nil)
+	assert.Nil(err)
+	assert.Equal("", text)
+	out, err = NewTable("Title").Format(TableJSON, nil)
+	assert.Nil(err)
+	assert.Equal("[]\n", out)
+
+	// rows longer than headers
+	table := NewTable("Title")
+	table.AddRow("Dune", "1965")
+	assert.Nil(table.SortBy(1, false))
+	text, err = table.Format(TableText, nil)
+	assert.Nil(err)
+	assert.Equal("Title\n───────────\nDune   1965\n", text)
+	out, err = table.Format(TableJSON, nil)
+	assert.Nil(err)
+	assert.Equal("[\n  {\n    \"2\": \"1965\",\n    \"Title\": \"Dune\"\n  }\n]\n", out)
+
+	// rows shorter than headers
+	table = &Table{Headers: []string{"Title", "Year"}, Rows: [][]string{{"Dune", "1965"}, {"Emma"}, {}}}
+	assert.Nil(table.SortBy(1, true))
+	text, err = table.Format(TableText, nil)
+	assert.Nil(err)
+	assert.Equal("Title  Year\n───────────\nDune   1965\nEmma\n\n", text)
+	out, err = table.Format(TableJSON, nil)
+	assert.Nil(err)
+	assert.Contains(out, `"Title": "Emma",
+    "Year": ""`)
+	out, err = table.Format(TableCSV, nil)
+	assert.Nil(err)
+	assert.Equal("Title,Year\nDune,1965\nEmma,\n,\n", out)
+	out, err = table.Format(TableMarkdown, nil)
+	assert.Nil(err)
+	assert.Contains(out, "| Emma |  |\n")
+}
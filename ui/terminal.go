@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// ColorMode defines when ANSI colours are used.
+type ColorMode int
+
+const (
+	// ColorAuto uses colours only if the environment and stdout allow it.
+	ColorAuto ColorMode = iota
+	// ColorAlways forces colours.
+	ColorAlways
+	// ColorNever disables colours.
+	ColorNever
+)
+
+// IsTerminal checks if w is a terminal.
+func IsTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// IsInteractive checks if both stdin and stdout are terminals.
+func IsInteractive() bool {
+	return IsTerminal(os.Stdin) && IsTerminal(os.Stdout)
+}
+
+// colorFromEnvironment decides if colours should be used for an output, from
+// the usual environment variables:
+// NO_COLOR disables colours, CLICOLOR_FORCE forces them, TERM=dumb disables
+// them, and otherwise colours are only used on terminals.
+func colorFromEnvironment(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("CLICOLOR_FORCE"); force != "" && force != "0" {
+		return true
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return IsTerminal(w)
+}
+
+// SetColor forces colours on or off, overriding the environment.
+func (ui *UI) SetColor(enabled bool) {
+	if enabled {
+		ui.Color = ColorAlways
+	} else {
+		ui.Color = ColorNever
+	}
+}
+
+// ColorEnabled checks if colours are used for output.
+func (ui UI) ColorEnabled() bool {
+	switch ui.Color {
+	case ColorAlways:
+		return true
+	case ColorNever:
+		return false
+	}
+	return colorFromEnvironment(os.Stdout)
+}
+
+// makeRaw puts a terminal in raw mode, and returns a function restoring its
+// previous state.
+func makeRaw(f *os.File) (restore func(), err error) {
+	fd := int(f.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		term.Restore(fd, state)
+	}, nil
+}
+
+// terminalSize returns the width and height of stdout, or sensible defaults.
+func terminalSize() (width, height int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width <= 0 || height <= 0 {
+		return 80, 24
+	}
+	return width, height
+}
@@ -0,0 +1,66 @@
+package ui
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setenv sets environment variables for a test, returning a function restoring them.
+func setenv(vars map[string]string) func() {
+	old := make(map[string]*string)
+	for k, v := range vars {
+		if previous, ok := os.LookupEnv(k); ok {
+			old[k] = &previous
+		} else {
+			old[k] = nil
+		}
+		if v == "" {
+			os.Unsetenv(k)
+		} else {
+			os.Setenv(k, v)
+		}
+	}
+	return func() {
+		for k, v := range old {
+			if v == nil {
+				os.Unsetenv(k)
+			} else {
+				os.Setenv(k, *v)
+			}
+		}
+	}
+}
+
+func TestUIColor(t *testing.T) {
+	fmt.Println("+ Testing UI/ColorEnabled()...")
+	assert := assert.New(t)
+	ui := &UI{}
+
+	// a buffer is not a terminal
+	assert.False(IsTerminal(&bytes.Buffer{}))
+
+	restore := setenv(map[string]string{"NO_COLOR": "", "CLICOLOR_FORCE": "1", "TERM": "xterm"})
+	assert.True(ui.ColorEnabled())
+	assert.Contains(ui.RedBold("red"), "\x1b[")
+	restore()
+
+	restore = setenv(map[string]string{"NO_COLOR": "1", "CLICOLOR_FORCE": "1"})
+	assert.False(ui.ColorEnabled())
+	assert.Equal("red", ui.RedBold("red"))
+	restore()
+
+	restore = setenv(map[string]string{"NO_COLOR": "", "CLICOLOR_FORCE": "", "TERM": "dumb"})
+	assert.False(ui.ColorEnabled())
+	// programmatic toggle overrides the environment
+	ui.SetColor(true)
+	assert.True(ui.ColorEnabled())
+	assert.NotEqual("red", ui.Red("red"))
+	ui.SetColor(false)
+	assert.False(ui.ColorEnabled())
+	assert.Equal("red", ui.Red("red"))
+	restore()
+}
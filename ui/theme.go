@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/ttacon/chalk"
+)
+
+var colors = map[string]chalk.Color{
+	"black":   chalk.Black,
+	"red":     chalk.Red,
+	"green":   chalk.Green,
+	"yellow":  chalk.Yellow,
+	"blue":    chalk.Blue,
+	"magenta": chalk.Magenta,
+	"cyan":    chalk.Cyan,
+	"white":   chalk.White,
+}
+
+// Style of a piece of text.
+// Color is one of black, red, green, yellow, blue, magenta, cyan, white, or
+// empty to keep the terminal default.
+type Style struct {
+	Color     string `json:"color,omitempty"`
+	Bold      bool   `json:"bold,omitempty"`
+	Underline bool   `json:"underline,omitempty"`
+}
+
+// Glyphs decorating the output.
+type Glyphs struct {
+	SubTitle     string `json:"subtitle"`
+	SubPartLeft  string `json:"subpart_left"`
+	SubPartRight string `json:"subpart_right"`
+	Error        string `json:"error"`
+	Warning      string `json:"warning"`
+}
+
+// Theme defines how every ui output method looks.
+type Theme struct {
+	Name         string `json:"name"`
+	Title        Style  `json:"title"`
+	SubTitle     Style  `json:"subtitle"`
+	SubPart      Style  `json:"subpart"`
+	SubPartFrame Style  `json:"subpart_frame"`
+	Choice       Style  `json:"choice"`
+	Usage        Style  `json:"usage"`
+	Error        Style  `json:"error"`
+	Warning      Style  `json:"warning"`
+	LocalTag     Style  `json:"local_tag"`
+	OnlineTag    Style  `json:"online_tag"`
+	// Sources are the styles of option source labels, by source name.
+	// LocalTag and OnlineTag are used for the current and online sources if
+	// they are not set.
+	Sources map[string]Style `json:"sources,omitempty"`
+	// Glyphs are used unless ASCII is set, in which case ASCIIGlyphs are.
+	Glyphs      Glyphs `json:"glyphs"`
+	ASCIIGlyphs Glyphs `json:"ascii_glyphs"`
+	ASCII       bool   `json:"ascii"`
+}
+
+var (
+	defaultGlyphs = Glyphs{
+		SubTitle:     " + ",
+		SubPartLeft:  " ──┤",
+		SubPartRight: "├──",
+		Error:        "ERROR: ",
+		Warning:      "WARNING: ",
+	}
+	defaultASCIIGlyphs = Glyphs{
+		SubTitle:     " + ",
+		SubPartLeft:  " --[",
+		SubPartRight: "]--",
+		Error:        "ERROR: ",
+		Warning:      "WARNING: ",
+	}
+
+	// ThemeDark is the default theme, for terminals with a dark background.
+	ThemeDark = Theme{
+		Name:         "dark",
+		Title:        Style{Color: "green", Bold: true},
+		SubTitle:     Style{Color: "green"},
+		SubPart:      Style{Color: "green", Bold: true},
+		SubPartFrame: Style{Color: "green"},
+		Choice:       Style{Color: "blue", Bold: true},
+		Usage:        Style{Color: "green"},
+		Error:        Style{Color: "red", Bold: true},
+		Warning:      Style{Color: "red"},
+		LocalTag:     Style{Color: "cyan", Bold: true},
+		OnlineTag:    Style{Color: "yellow", Bold: true},
+		Glyphs:       defaultGlyphs,
+		ASCIIGlyphs:  defaultASCIIGlyphs,
+	}
+	// ThemeLight is meant for terminals with a light background.
+	ThemeLight = Theme{
+		Name:         "light",
+		Title:        Style{Color: "blue", Bold: true},
+		SubTitle:     Style{Color: "blue"},
+		SubPart:      Style{Color: "magenta", Bold: true},
+		SubPartFrame: Style{Color: "magenta"},
+		Choice:       Style{Color: "black", Bold: true},
+		Usage:        Style{Color: "blue"},
+		Error:        Style{Color: "red", Bold: true},
+		Warning:      Style{Color: "magenta"},
+		LocalTag:     Style{Color: "blue", Bold: true},
+		OnlineTag:    Style{Color: "magenta", Bold: true},
+		Glyphs:       defaultGlyphs,
+		ASCIIGlyphs:  defaultASCIIGlyphs,
+	}
+	// ThemeMonochrome only relies on bold and underlined text.
+	ThemeMonochrome = Theme{
+		Name:        "monochrome",
+		Title:       Style{Bold: true, Underline: true},
+		SubPart:     Style{Bold: true},
+		Choice:      Style{Bold: true},
+		Error:       Style{Bold: true},
+		Warning:     Style{Underline: true},
+		LocalTag:    Style{Bold: true},
+		OnlineTag:   Style{Underline: true},
+		Glyphs:      defaultGlyphs,
+		ASCIIGlyphs: defaultASCIIGlyphs,
+	}
+	// ThemeHighContrast uses bright, bold text everywhere.
+	ThemeHighContrast = Theme{
+		Name:         "high-contrast",
+		Title:        Style{Color: "yellow", Bold: true, Underline: true},
+		SubTitle:     Style{Color: "white", Bold: true},
+		SubPart:      Style{Color: "yellow", Bold: true},
+		SubPartFrame: Style{Color: "white", Bold: true},
+		Choice:       Style{Color: "cyan", Bold: true},
+		Usage:        Style{Color: "white", Bold: true},
+		Error:        Style{Color: "red", Bold: true, Underline: true},
+		Warning:      Style{Color: "yellow", Bold: true},
+		LocalTag:     Style{Color: "cyan", Bold: true, Underline: true},
+		OnlineTag:    Style{Color: "yellow", Bold: true, Underline: true},
+		Glyphs:       defaultGlyphs,
+		ASCIIGlyphs:  defaultASCIIGlyphs,
+	}
+
+	// Themes available by name.
+	Themes = map[string]*Theme{
+		ThemeDark.Name:         &ThemeDark,
+		ThemeLight.Name:        &ThemeLight,
+		ThemeMonochrome.Name:   &ThemeMonochrome,
+		ThemeHighContrast.Name: &ThemeHighContrast,
+	}
+)
+
+// LoadTheme reads a theme from a JSON configuration file.
+// The file can set "base" to the name of a built-in theme (dark by default),
+// and only override some of its fields.
+func LoadTheme(path string) (*Theme, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var base struct {
+		Base string `json:"base"`
+	}
+	if err := json.Unmarshal(data, &base); err != nil {
+		return nil, err
+	}
+	if base.Base == "" {
+		base.Base = ThemeDark.Name
+	}
+	builtin, ok := Themes[base.Base]
+	if !ok {
+		return nil, fmt.Errorf("unknown base theme %q", base.Base)
+	}
+	theme := *builtin
+	// do not modify the sources of the built-in theme
+	theme.Sources = make(map[string]Style, len(builtin.Sources))
+	for name, s := range builtin.Sources {
+		theme.Sources[name] = s
+	}
+	if err := json.Unmarshal(data, &theme); err != nil {
+		return nil, err
+	}
+	styles := []Style{theme.Title, theme.SubTitle, theme.SubPart, theme.SubPartFrame, theme.Choice, theme.Usage, theme.Error, theme.Warning, theme.LocalTag, theme.OnlineTag}
+	for _, s := range theme.Sources {
+		styles = append(styles, s)
+	}
+	for _, s := range styles {
+		if _, ok := colors[s.Color]; s.Color != "" && !ok {
+			return nil, fmt.Errorf("unknown color %q", s.Color)
+		}
+	}
+	return &theme, nil
+}
+
+// theme currently used.
+func (ui UI) theme() *Theme {
+	if ui.Theme == nil {
+		return &ThemeDark
+	}
+	return ui.Theme
+}
+
+// glyphs of the current theme.
+func (ui UI) glyphs() Glyphs {
+	t := ui.theme()
+	if t.ASCII {
+		return t.ASCIIGlyphs
+	}
+	return t.Glyphs
+}
+
+// Style a string, if colours are enabled.
+func (ui UI) Style(s Style, in string) string {
+	if !ui.ColorEnabled() {
+		return in
+	}
+	if c, ok := colors[s.Color]; ok {
+		in = c.Color(in)
+	}
+	if s.Underline {
+		in = chalk.Underline.TextStyle(in)
+	}
+	if s.Bold {
+		in = chalk.Bold.TextStyle(in)
+	}
+	return in
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUITheme(t *testing.T) {
+	fmt.Println("+ Testing UI/Theme...")
+	assert := assert.New(t)
+
+	ui := &UI{Color: ColorAlways}
+	assert.Equal(ui.GreenBold("title"), ui.Style(ui.theme().Title, "title"), "default theme should not change output")
+	assert.Equal(ui.CyanBold(LocalTag)+"entry", ui.Tag("entry", true))
+	assert.Equal("entry", ui.unTag(ui.Tag("entry", true)))
+
+	ui.Theme = &ThemeMonochrome
+	assert.NotContains(ui.Style(ui.theme().Title, "title"), "\x1b[3", "monochrome theme should not use colours")
+	assert.Equal(" ──┤", ui.glyphs().SubPartLeft)
+
+	ui.Color = ColorNever
+	assert.Equal("title", ui.Style(ThemeHighContrast.Title, "title"))
+
+	// loading from file
+	config, err := ioutil.TempFile("", "theme")
+	require.Nil(t, err)
+	defer os.Remove(config.Name())
+	_, err = config.WriteString(`{"base": "light", "name": "custom", "title": {"color": "red"}, "ascii": true}`)
+	require.Nil(t, err)
+	require.Nil(t, config.Close())
+
+	theme, err := LoadTheme(config.Name())
+	require.Nil(t, err)
+	assert.Equal("custom", theme.Name)
+	assert.Equal(Style{Color: "red", Bold: true}, theme.Title, "only the color should be overridden")
+	assert.Equal(ThemeLight.Choice, theme.Choice)
+	ui.Theme = theme
+	assert.Equal(" --[", ui.glyphs().SubPartLeft)
+	assert.Equal("dark", ThemeDark.Name, "built-in theme should not be modified")
+
+	require.Nil(t, ioutil.WriteFile(config.Name(), []byte(`{"title": {"color": "pink"}}`), 0600))
+	_, err = LoadTheme(config.Name())
+	assert.NotNil(err)
+	require.Nil(t, ioutil.WriteFile(config.Name(), []byte(`{"base": "unknown"}`), 0600))
+	_, err = LoadTheme(config.Name())
+	assert.NotNil(err)
+}
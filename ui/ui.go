@@ -4,9 +4,26 @@ Package ui is the endive subpackage that implements the UserInterface.
 It aims at providing functions to present data to the user and get the
 necessary user input.
 
-Displaying large amounts of data relies on `less`, editing large texts relies
-on `$EDITOR`, falling back to `nano` if the variable is not found.
+Displaying large amounts of data relies on `$PAGER`, falling back to `less`
+or a built-in pager, if the text does not fit on the screen. Editing large
+texts relies on `$VISUAL` or `$EDITOR`, falling back to common editors if
+they are not set.
 
+Colours are only used on terminals, unless forced with CLICOLOR_FORCE or
+disabled with NO_COLOR or TERM=dumb; see UI.Color to override this.
+
+Messages are translated according to LC_ALL, LC_MESSAGES or LANG, English and
+French being bundled; see Catalog and UI.Language.
+
+Logs are written to a file in the XDG data directory by InitLogger, and can
+also be sent to journald or syslog; see UI.Log.
+
+UI.HandleSignals restores the terminal, finalizes what is being displayed and
+closes the log file if the program is interrupted; see RegisterCleanup.
+
+A Recorder writes every prompt and answer of a session to a file, and a
+Replayer answers the same prompts from it in a later, non-interactive run,
+reporting where the session diverges from the recording.
 */
 package ui
 
@@ -15,9 +32,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
 	"os"
-	"os/exec"
 	"strconv"
 	"strings"
 
@@ -33,6 +48,9 @@ const (
 	tooManyErrors = "Too many errors, giving up."
 	userAborted   = "User aborted."
 
+	// maxErrors is the number of invalid answers accepted before giving up.
+	maxErrors = 10
+
 	// LocalTag an option to show it's the value in current database
 	LocalTag = "[current] "
 	// OnlineTag an option to show it's from GR
@@ -45,8 +63,36 @@ type UI struct {
 	logger *logging.Logger
 	// LogFile is the pointer to the log file, to be closed by the main function.
 	logFile *os.File
+	// logConns are the sockets to journald or syslog, if enabled.
+	logConns []*datagramConn
+	// Log configures where logs are sent, only to a file by default.
+	Log LogOptions
+	// Color defines when output is coloured, depending on the environment by default.
+	Color ColorMode
+	// Theme defines the output styles, ThemeDark by default.
+	Theme *Theme
+	// Pager configures how long texts are displayed.
+	Pager Pager
+	// Language of messages, from the environment if empty.
+	Language string
+	// Automation enables the non-interactive mode, which can also be
+	// enabled with environment variables.
+	Automation Automation
+	// input is where user input is read, stdin by default.
+	input *bufio.Reader
+	// history of inputs, if line editing is enabled.
+	history *History
+	// state of the session, shared by copies of the UI.
+	state *session
+	// journal of decisions, if enabled.
+	journal *Journal
+	// replay of decisions from a previous journal, if any.
+	replay *decisionReplay
 }
 
+// stdin is shared so that buffered input is not lost between calls.
+var stdin = bufio.NewReader(os.Stdin)
+
 // RemoveDuplicates in []string
 func RemoveDuplicates(options *[]string, otherStringsToClean ...string) {
 	found := make(map[string]bool)
@@ -66,256 +112,261 @@ func RemoveDuplicates(options *[]string, otherStringsToClean ...string) {
 }
 
 // SelectOption among several, or input a new one, and return user input.
+// Options can be tagged with Tag; SelectFrom accepts options from any source.
 func (ui UI) SelectOption(title, usage string, options []string, longField bool) (string, error) {
+	choice, err := ui.SelectFrom(title, usage, ui.toOptions(options), longField)
+	return choice.Value, err
+}
+
+// SelectFrom among several options, or input a new one, and return the
+// selected option. Options input by the user have no source.
+func (ui UI) SelectFrom(title, usage string, options []Option, longField bool) (Option, error) {
 	ui.SubPart(title)
 	if usage != "" {
-		fmt.Println(ui.Green(usage))
+		fmt.Println(ui.Style(ui.theme().Usage, usage))
 	}
 
 	// remove duplicates from options and display them
-	RemoveDuplicates(&options)
+	options = removeDuplicateOptions(options)
+	current, _ := currentOption(options)
+	if d, ok := ui.replayed(title); ok {
+		choice, err := replayedOption(d, options)
+		ui.decide(title, current.Value, optionValues(options), choice.Value, replayMethod(d))
+		return choice, err
+	}
+	choice, method, err := ui.selectFrom(title, options, longField)
+	if method != "" {
+		ui.decide(title, current.Value, optionValues(options), choice.Value, method)
+	}
+	return choice, err
+}
+
+// selectFrom asks the user to select an option, returning how it was chosen.
+// The method is empty if the selection failed for another reason than
+// the user giving up.
+func (ui UI) selectFrom(title string, options []Option, longField bool) (Option, DecisionMethod, error) {
+	if a, auto := ui.automation(); auto {
+		choice, err := ui.autoSelect(title, options, a.Select)
+		if err != nil {
+			return choice, "", err
+		}
+		return choice, DecisionAutomatic, nil
+	}
+	// show differences between candidates and the current value, if any
+	current, hasCurrent := currentOption(options)
 	for i, o := range options {
-		fmt.Printf("%d. %s\n", i+1, o)
+		value := o.Value
+		if hasCurrent && o.Source != SourceLocal && value != current.Value {
+			value = ui.Diff(current.Value, value)
+		}
+		fmt.Printf("%d. %s%s%s\n", i+1, ui.sourceLabel(o), value, ui.metadata(o))
 	}
 
-	var choice string
 	errs := 0
-	validChoice := false
-	for !validChoice {
+	for {
 		if len(options) == 0 {
-			ui.Choice("Leave [B]lank, [E]dit manually, or [A]bort: ")
+			ui.Choice(ui.T(selectNoOption))
 		} else if len(options) > 1 {
-			ui.Choice("Choose option [1-%d], leave [B]lank, [E]dit manually, or [A]bort: ", len(options))
+			ui.Choice(ui.T(selectOptions), len(options))
 		} else {
-			ui.Choice("Choose [1], leave [B]lank, [E]dit manually, or [A]bort: ")
+			ui.Choice(ui.T(selectOneOption))
 		}
 		choice, scanErr := ui.GetInput()
 		if scanErr != nil {
-			return "", scanErr
+			return Option{}, "", scanErr
 		}
 
-		if strings.ToUpper(choice) == "E" {
+		if ui.isShortcut(choice, selectNoOption, "E") {
 			var edited string
 			var scanErr error
 			if longField {
 				allVersions := ""
 				for i, o := range options {
-					allVersions += fmt.Sprintf("--- %d ---\n%s\n", i+1, ui.unTag(o))
+					allVersions += fmt.Sprintf("--- %d ---\n%s\n", i+1, o.Value)
 				}
 				edited, scanErr = ui.Edit(allVersions)
 			} else {
-				ui.Choice(enterNewValue)
+				ui.Choice(ui.T(enterNewValue))
 				edited, scanErr = ui.GetInput()
 			}
 			if scanErr != nil {
-				return "", scanErr
+				return Option{}, "", scanErr
 			}
 			if edited == "" {
-				ui.Warning(emptyValue)
+				ui.Warning(ui.T(emptyValue))
+			} else if hasCurrent {
+				ui.showDiff(current.Value, edited, longField)
 			}
-			confirmed := ui.Accept("Confirm: " + edited)
+			confirmed := ui.Accept(ui.T(confirmValue, edited))
 			if confirmed {
-				return edited, nil
+				return Option{Value: edited}, editMethod(edited, longField), nil
 			}
-			ui.Warning(notConfirmed)
-		} else if strings.ToUpper(choice) == "A" {
-			return "", errors.New(userAborted)
-		} else if strings.ToUpper(choice) == "B" {
-			return "", nil
+			ui.Warning(ui.T(notConfirmed))
+			continue
+		} else if ui.isShortcut(choice, selectNoOption, "A") {
+			return Option{}, DecisionAborted, errors.New(userAborted)
+		} else if ui.isShortcut(choice, selectNoOption, "B") {
+			return Option{}, DecisionBlank, nil
 		} else if index, err := strconv.Atoi(choice); err == nil && 0 < index && index <= len(options) {
-			return ui.unTag(options[index-1]), nil
+			if options[index-1].Source == SourceLocal {
+				return options[index-1], DecisionKept, nil
+			}
+			return options[index-1], DecisionSelected, nil
 		}
 
-		if !validChoice {
-			ui.Warning(invalidChoice)
-			errs++
-			if errs > 10 {
-				ui.Warning(tooManyErrors)
-				return "", errors.New(invalidChoice)
-			}
+		ui.Warning(ui.T(invalidChoice))
+		errs++
+		if errs > maxErrors {
+			ui.Warning(ui.T(tooManyErrors))
+			return Option{}, DecisionAborted, errors.New(invalidChoice)
 		}
 	}
-	return choice, nil
 }
 
 // UpdateValue with user input
 func (ui UI) UpdateValue(field, usage, oldValue string, longField bool) (newValue string, err error) {
-	ui.SubPart("Modifying " + field)
+	ui.SubPart(ui.T(modifying, field))
 	if usage != "" {
-		ui.Info(ui.Green(usage)) // TODO ui.Info dans SelectOption aussi!
+		ui.Info(ui.Style(ui.theme().Usage, usage)) // TODO ui.Info dans SelectOption aussi!
 	}
-	fmt.Printf("Current value: %s\n", oldValue)
+	fmt.Print(ui.T(currentValue, oldValue))
+	if d, ok := ui.replayed(field); ok {
+		switch {
+		case d.Method == DecisionAborted:
+			newValue, err = "", errors.New(userAborted)
+		case keepsCurrent(d):
+			newValue, err = strings.TrimSpace(oldValue), nil
+		default:
+			newValue, err = d.Value, nil
+		}
+		ui.decide(field, oldValue, nil, newValue, replayMethod(d))
+		return newValue, err
+	}
+	newValue, method, err := ui.updateValue(field, oldValue, longField)
+	if method != "" {
+		ui.decide(field, oldValue, nil, newValue, method)
+	}
+	return newValue, err
+}
 
+// updateValue asks the user to keep or edit a value, returning how it was
+// chosen. The method is empty if it failed for another reason than the user
+// giving up.
+func (ui UI) updateValue(field, oldValue string, longField bool) (string, DecisionMethod, error) {
+	if _, auto := ui.automation(); auto {
+		ui.logDecision("%s: kept current value", field)
+		return strings.TrimSpace(oldValue), DecisionAutomatic, nil
+	}
+
+	var newValue string
+	var method DecisionMethod
 	validChoice := false
 	errs := 0
 	for !validChoice {
-		ui.Choice(editOrKeep)
+		ui.Choice(ui.T(editOrKeep))
 		choice, scanErr := ui.GetInput()
 		if scanErr != nil {
-			return newValue, scanErr
+			return "", "", scanErr
 		}
-		switch strings.ToLower(choice) {
-		case "e":
+		switch {
+		case ui.isShortcut(choice, editOrKeep, "E"):
 			var choice string
 			var scanErr error
 			if longField {
 				choice, scanErr = ui.Edit(oldValue)
 			} else {
-				ui.Choice(enterNewValue)
+				ui.Choice(ui.T(enterNewValue))
 				choice, scanErr = ui.GetInput()
 			}
 			if scanErr != nil {
-				return newValue, scanErr
+				return "", "", scanErr
 			}
 			if choice == "" {
-				ui.Warning(emptyValue)
+				ui.Warning(ui.T(emptyValue))
+			} else {
+				ui.showDiff(oldValue, choice, longField)
 			}
-			if ui.Accept("Confirm") {
+			if ui.Accept(ui.T(confirm)) {
 				newValue = choice
+				method = editMethod(strings.TrimSpace(choice), longField)
 				validChoice = true
 			} else {
-				ui.Warning(notConfirmed)
-				ui.Choice(editOrKeep)
+				ui.Warning(ui.T(notConfirmed))
+				ui.Choice(ui.T(editOrKeep))
 			}
-		case "k":
+		case ui.isShortcut(choice, editOrKeep, "K"):
 			newValue = oldValue
+			method = DecisionKept
 			validChoice = true
 		default:
-			ui.Warning(invalidChoice)
+			ui.Warning(ui.T(invalidChoice))
 			errs++
-			if errs > 10 {
-				return "", errors.New(invalidChoice)
+			if errs > maxErrors {
+				return "", DecisionAborted, errors.New(invalidChoice)
 			}
 		}
 	}
-	return strings.TrimSpace(newValue), nil
+	return strings.TrimSpace(newValue), method, nil
 }
 
-// GetInput from user
-func (ui UI) GetInput() (string, error) {
-	scanner := bufio.NewReader(os.Stdin)
-	choice, scanErr := scanner.ReadString('\n')
-	return strings.TrimSpace(choice), scanErr
+// SetInput reads user input from r instead of stdin.
+func (ui *UI) SetInput(r io.Reader) {
+	ui.input = bufio.NewReader(r)
 }
 
-// Accept asks a question and returns the answer
-func (ui UI) Accept(question string) bool {
-	fmt.Printf(ui.BlueBold("%s Y/N : "), question)
-	input, err := ui.GetInput()
-	if err == nil {
-		switch input {
-		case "y", "Y", "yes":
-			return true
-		}
+// reader for user input.
+func (ui UI) reader() *bufio.Reader {
+	if ui.input == nil {
+		return stdin
 	}
-	return false
+	return ui.input
 }
 
-// Display text through a pager if necessary.
-func (ui UI) Display(output string) {
-	// -e Causes less to automatically exit the second time it reaches end-of-file.
-	// -F or --quit-if-one-screen  Causes less to automatically exit if the entire file can be displayed on the first screen.
-	// -Q Causes totally "quiet" operation: the terminal bell is never rung.
-	// -X or --no-init Disables sending the termcap initialization and deinitialization strings to the terminal. This is sometimes desirable if the deinitialization string does something unnecessary, like clearing the screen.
-	cmd := exec.Command("less", "-e", "-F", "-Q", "-X", "--buffers=-1")
-	if err := runCommand(cmd, output); err != nil {
-		ui.Error(err.Error())
-	}
+// GetInput from user
+func (ui UI) GetInput() (string, error) {
+	return ui.getLine(nil, false)
 }
 
-func runCommand(cmd *exec.Cmd, output string) error {
-	r, stdin := io.Pipe()
-	cmd.Stdin = r
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	// create a blocking chan, run the pager and unblock once it is finished
-	c := make(chan error)
-	go func() {
-		defer close(c)
-		c <- cmd.Run()
-	}()
-	// create a channel to write output to less, because for large amounts of
-	// lines, it's blocking until less displays what was already sent
-	d := make(chan error)
-	go func() {
-		defer close(d)
-		_, err := io.WriteString(stdin, output)
-		if err != nil {
-			return
-		}
-		d <- err
-	}()
-	// either wait for write to finish or for user to quit less
-	select {
-	case <-d:
-		// if write is finished, wait for user to quit
-	case <-c:
-		// if user quits first, stop writing
-		close(d)
-	}
-	// close stdin
-	if err := stdin.Close(); err != nil {
-		return err
-	}
-	// wait for the pager to be finished
-	return <-c
+// readInputLine without line editing.
+func (ui UI) readInputLine() (string, error) {
+	choice, scanErr := ui.reader().ReadString('\n')
+	return strings.TrimSpace(choice), scanErr
 }
 
-// Edit long value using external $EDITOR
-func (ui *UI) Edit(oldValue string) (string, error) {
-	// create temp file
-	content := []byte(oldValue)
-	tmpfile, err := ioutil.TempFile("", "edit")
-	if err != nil {
-		return oldValue, err
-	}
-	// clean up
-	defer tmpfile.Close()
-	defer os.Remove(tmpfile.Name())
-
-	// write input string inside
-	if _, err := tmpfile.Write(content); err != nil {
-		return oldValue, err
-	}
-	if err := tmpfile.Close(); err != nil {
-		return oldValue, err
-	}
-
-	// find $EDITOR
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		ui.Warning("$EDITOR not set, falling back to nano")
-		editor = "nano"
-	}
-
-	// open it with $EDITOR
-	cmd := exec.Command(editor, tmpfile.Name())
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	if err := cmd.Run(); err != nil {
-		return oldValue, err
-	}
-
-	// read file back, set output string
-	newContent, err := ioutil.ReadFile(tmpfile.Name())
-	if err != nil {
-		return oldValue, err
+// Accept asks a question and returns the answer
+func (ui UI) Accept(question string) bool {
+	if a, auto := ui.automation(); auto {
+		ui.logDecision("%s: answered %t", question, a.AssumeYes)
+		return a.AssumeYes
 	}
-	return strings.TrimSpace(string(newContent)), nil
+	yes, no, _, _ := ui.answers()
+	ui.Choice("%s %s/%s : ", question, strings.ToUpper(yes[0]), strings.ToUpper(no[0]))
+	input, err := ui.GetInput()
+	return err == nil && isAnswer(input, yes)
 }
 
 // Tag an entry local or online
 func (ui *UI) Tag(entry string, isLocal bool) string {
 	if isLocal {
-		return ui.CyanBold(LocalTag) + entry
+		return ui.sourceLabel(Option{Source: SourceLocal}) + entry
 	}
-	return ui.YellowBold(OnlineTag) + entry
+	return ui.sourceLabel(Option{Source: SourceOnline}) + entry
 }
 
 // unTag strings tagged with Tag.
 func (ui *UI) unTag(option string) string {
-	out := option
-	out = strings.Replace(out, ui.CyanBold(LocalTag), "", -1)
-	out = strings.Replace(out, ui.YellowBold(OnlineTag), "", -1)
-	return strings.TrimSpace(out)
+	value, _ := ui.splitTag(option)
+	return value
+}
+
+// splitTag returns the value and source of a string tagged with Tag.
+// Only a label at the start of the string is a tag.
+func (ui UI) splitTag(option string) (value, source string) {
+	for _, source := range []string{SourceLocal, SourceOnline} {
+		label := ui.sourceLabel(Option{Source: source})
+		if strings.HasPrefix(option, label) {
+			return strings.TrimSpace(strings.TrimPrefix(option, label)), source
+		}
+	}
+	return strings.TrimSpace(option), ""
 }